@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"crypto-tracker/models"
+	"crypto-tracker/services/chats"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequireAdminToken protegge le routes di amministrazione richiedendo l'header "Authorization: Bearer <token>"
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": localize(c, "api.unauthorized")})
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetChats restituisce le chat registrate dal bot, dalle più attive di recente,
+// filtrabili per stato (active o blocked) e paginate con limit e offset
+func GetChats(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := chats.Filter{Limit: chats.DefaultListLimit}
+
+		if value := c.Query("status"); value != "" {
+			status, err := models.ParseChatStatus(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+				return
+			}
+			filter.Status = status
+		}
+
+		if value := c.Query("limit"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 || n > chats.MaxListLimit {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.invalid_limit", chats.MaxListLimit)})
+				return
+			}
+			filter.Limit = n
+		}
+
+		if value := c.Query("offset"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.invalid_offset")})
+				return
+			}
+			filter.Offset = n
+		}
+
+		list, total, err := chats.List(db, filter)
+		if err != nil {
+			log.Printf("Errore nel recupero delle chat: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.chats_error")})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"total": total, "limit": filter.Limit, "offset": filter.Offset, "chats": list})
+	}
+}
+
+// GetChat restituisce una chat registrata dal bot
+func GetChat(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.chat_not_found")})
+			return
+		}
+
+		var chat models.Chat
+		if err := db.First(&chat, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.chat_not_found")})
+				return
+			}
+			log.Printf("Errore nel recupero della chat %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.chats_error")})
+			return
+		}
+
+		c.JSON(http.StatusOK, chat)
+	}
+}
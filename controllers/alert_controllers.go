@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"crypto-tracker/i18n"
 	"crypto-tracker/models"
-	"fmt"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/portfolio"
+	"crypto-tracker/services/pricing"
 	"log"
 	"net/http"
 	"strconv"
@@ -12,45 +15,178 @@ import (
 	"gorm.io/gorm"
 )
 
-func CreateAlert(db *gorm.DB) gin.HandlerFunc {
+// alertResponse è la risposta di creazione di un alert, con un eventuale avviso
+// (es. condizione già soddisfatta dal prezzo attuale)
+type alertResponse struct {
+	models.Alert
+	Warning string `json:"warning,omitempty"`
+}
+
+// eventResponse è un evento della cronologia di un alert con la sua descrizione nella lingua della richiesta
+type eventResponse struct {
+	models.AlertEvent
+	Description string `json:"description,omitempty"`
+}
+
+func CreateAlert(db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Struttura per il binding dell'input
 		var input struct {
-			CryptoID       string  `json:"crypto_id" binding:"required"`
-			ThresholdPrice float64 `json:"threshold_price" binding:"required"`
-			UserChatID     int64   `json:"user_chat_id"`
+			CryptoID          string     `json:"crypto_id"`
+			Type              string     `json:"type"`
+			ThresholdPrice    float64    `json:"threshold_price"`
+			PercentChange     float64    `json:"percent_change"`
+			Baseline          string     `json:"baseline"`
+			Currency          string     `json:"currency"`
+			Direction         string     `json:"direction"`
+			TriggerMode       string     `json:"trigger_mode"`
+			Recurring         bool       `json:"recurring"`
+			CooldownMinutes   int        `json:"cooldown_minutes"`
+			HysteresisPercent float64    `json:"hysteresis_percent"`
+			MaxFires          int        `json:"max_fires"`
+			ActiveFrom        *time.Time `json:"active_from"`
+			ExpiresAt         *time.Time `json:"expires_at"`
+			UserChatID        int64      `json:"user_chat_id"`
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		alertType, err := models.ParseAlertType(input.Type)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		baseline, err := models.ParseBaseline(input.Baseline)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		switch {
+		case alertType != models.AlertTypePortfolioValue && input.CryptoID == "":
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.crypto_id_required")})
+			return
+		case (alertType == models.AlertTypePrice || alertType == models.AlertTypePortfolioValue) && input.ThresholdPrice <= 0:
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.threshold_required", alertType)})
+			return
+		case alertType == models.AlertTypePercent && input.PercentChange <= 0:
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.percent_change_required")})
+			return
+		case alertType == models.AlertTypePositionPnL && input.PercentChange == 0:
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.pnl_percent_required")})
+			return
+		}
+
+		// Gli alert sul P&L usano la percentuale come soglia: positiva al rialzo, negativa al ribasso
+		if alertType == models.AlertTypePositionPnL {
+			input.ThresholdPrice, input.PercentChange = input.PercentChange, 0
+			if input.Direction == "" && input.ThresholdPrice < 0 {
+				input.Direction = models.DirectionBelow
+			}
+		}
+		if alertType == models.AlertTypePortfolioValue {
+			input.CryptoID = ""
+		} else {
+			// Accetta anche simboli e nomi (es. "BTC" o "Bitcoin")
+			input.CryptoID = registry.Resolve(input.CryptoID)
+		}
+
+		if input.CooldownMinutes < 0 || input.HysteresisPercent < 0 || input.MaxFires < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.negative_recurrence")})
 			return
 		}
 
-		// Verifica che l'ID della criptovaluta esista ottenendo il prezzo attuale
-		price, err := GetPriceUSD(input.CryptoID)
+		if err := validateAlertWindow(input.ActiveFrom, input.ExpiresAt); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		// Le opzioni di ricorrenza implicano un alert ricorrente
+		recurring := input.Recurring || input.CooldownMinutes > 0 || input.HysteresisPercent > 0 || input.MaxFires > 0
+
+		// Gli alert percentuali senza direzione scattano sia al rialzo sia al ribasso
+		if alertType == models.AlertTypePercent && input.Direction == "" {
+			input.Direction = models.DirectionCross
+		}
+
+		currency, err := pricing.ParseCurrency(input.Currency)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Impossibile ottenere il prezzo per la criptovaluta fornita. Verifica che l'ID sia corretto."})
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		direction, err := models.ParseDirection(input.Direction)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		triggerMode, err := models.ParseTriggerMode(input.TriggerMode)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
 			return
 		}
 
 		// Crea l'alert
 		alert := models.Alert{
-			CryptoID:       input.CryptoID,
-			ThresholdPrice: input.ThresholdPrice,
-			CurrentPrice:   price,
-			Triggered:      false,
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-			UserChatID:     input.UserChatID,
+			CryptoID:          input.CryptoID,
+			Type:              alertType,
+			Currency:          currency,
+			Direction:         direction,
+			TriggerMode:       triggerMode,
+			ThresholdPrice:    input.ThresholdPrice,
+			PercentChange:     input.PercentChange,
+			Baseline:          baseline,
+			Triggered:         false,
+			Recurring:         recurring,
+			CooldownMinutes:   input.CooldownMinutes,
+			HysteresisPercent: input.HysteresisPercent,
+			MaxFires:          input.MaxFires,
+			ActiveFrom:        input.ActiveFrom,
+			ExpiresAt:         input.ExpiresAt,
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+			UserChatID:        input.UserChatID,
+		}
+
+		if alert.IsPortfolio() {
+			// Per gli alert sul portafoglio il valore attuale deriva dalle posizioni dell'utente
+			value, err := portfolio.Current(c.Request.Context(), db, prices, &alert)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.portfolio_valuation_error", err)})
+				return
+			}
+			alert.Observe(value, time.Now().UTC())
+		} else {
+			// Verifica che l'ID della criptovaluta esista ottenendo il prezzo attuale
+			quote, err := pricing.GetQuote(c.Request.Context(), prices, alert.CryptoID, currency)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.price_unavailable")})
+				return
+			}
+			alert.Observe(quote.Price, time.Now().UTC())
+
+			// Gli alert percentuali registrano il prezzo di riferimento al momento della creazione
+			if alertType == models.AlertTypePercent {
+				alert.ThresholdPrice = 0
+				if err := alert.SetReference(quote.Price, quote.Change24h); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+					return
+				}
+			}
 		}
 
 		if err := db.Create(&alert).Error; err != nil {
 			log.Printf("Errore nella creazione dell'alert: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Errore nella creazione dell'alert: %v", err)})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "alert.create_error", err)})
 			return
 		}
 
-		c.JSON(http.StatusCreated, alert)
+		c.JSON(http.StatusCreated, alertResponse{Alert: alert, Warning: alert.CreationWarning(requestLanguage(c), alert.CurrentPrice)})
 	}
 }
 
@@ -68,12 +204,12 @@ func GetAlerts(db *gorm.DB) gin.HandlerFunc {
 		}
 
 		if err := query.Find(&alerts).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Errore nel recupero degli alert"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.alerts_error")})
 			return
 		}
 
 		if len(alerts) == 0 {
-			c.JSON(http.StatusOK, gin.H{"message": "Nessun alert trovato"})
+			c.JSON(http.StatusOK, gin.H{"message": localize(c, "api.no_alerts")})
 			return
 		}
 
@@ -87,7 +223,7 @@ func GetAlert(db *gorm.DB) gin.HandlerFunc {
 		var alert models.Alert
 
 		if err := db.First(&alert, id).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"message": "Nessun alert trovato"})
+			c.JSON(http.StatusNotFound, gin.H{"message": localize(c, "api.no_alerts")})
 			return
 		}
 
@@ -95,67 +231,215 @@ func GetAlert(db *gorm.DB) gin.HandlerFunc {
 	}
 }
 
-func UpdateAlert(db *gorm.DB) gin.HandlerFunc {
+func UpdateAlert(db *gorm.DB, prices pricing.PriceProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, _ := strconv.Atoi(c.Param("id"))
 		var alert models.Alert
 
 		if err := db.First(&alert, id).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Alert non trovato"})
+			c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.alert_not_found")})
 			return
 		}
+		before := alert
 
 		var input struct {
-			ThresholdPrice float64 `json:"threshold_price"`
-			Triggered      bool    `json:"triggered"`
+			ThresholdPrice    *float64   `json:"threshold_price"`
+			PercentChange     *float64   `json:"percent_change"`
+			Direction         string     `json:"direction"`
+			TriggerMode       string     `json:"trigger_mode"`
+			Triggered         *bool      `json:"triggered"`
+			Recurring         *bool      `json:"recurring"`
+			CooldownMinutes   *int       `json:"cooldown_minutes"`
+			HysteresisPercent *float64   `json:"hysteresis_percent"`
+			MaxFires          *int       `json:"max_fires"`
+			ActiveFrom        *time.Time `json:"active_from"`
+			ExpiresAt         *time.Time `json:"expires_at"`
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
 			return
 		}
 
-		// Aggiorna i campi
-		alert.ThresholdPrice = input.ThresholdPrice
-		alert.Triggered = input.Triggered
+		// La direzione è opzionale: se assente resta quella attuale
+		if input.Direction != "" {
+			direction, err := models.ParseDirection(input.Direction)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+				return
+			}
+			alert.Direction = direction
+		}
+
+		// Anche la modalità è opzionale
+		if input.TriggerMode != "" {
+			triggerMode, err := models.ParseTriggerMode(input.TriggerMode)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+				return
+			}
+			alert.TriggerMode = triggerMode
+		}
+
+		// Le opzioni di ricorrenza sono opzionali: vengono aggiornate solo quelle presenti
+		if (input.CooldownMinutes != nil && *input.CooldownMinutes < 0) ||
+			(input.HysteresisPercent != nil && *input.HysteresisPercent < 0) ||
+			(input.MaxFires != nil && *input.MaxFires < 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.negative_recurrence")})
+			return
+		}
+		if input.Recurring != nil {
+			alert.Recurring = *input.Recurring
+		}
+		if input.CooldownMinutes != nil {
+			alert.CooldownMinutes = *input.CooldownMinutes
+		}
+		if input.HysteresisPercent != nil {
+			alert.HysteresisPercent = *input.HysteresisPercent
+		}
+		if input.MaxFires != nil {
+			alert.MaxFires = *input.MaxFires
+		}
+
+		// Anche la finestra di attivazione è opzionale; una nuova scadenza futura riattiva un alert scaduto
+		activeFrom, expiresAt := alert.ActiveFrom, alert.ExpiresAt
+		if input.ActiveFrom != nil {
+			activeFrom = input.ActiveFrom
+		}
+		if input.ExpiresAt != nil {
+			expiresAt = input.ExpiresAt
+		}
+		if input.ActiveFrom != nil || input.ExpiresAt != nil {
+			if err := validateAlertWindow(activeFrom, expiresAt); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+				return
+			}
+			alert.ActiveFrom, alert.ExpiresAt = activeFrom, expiresAt
+			alert.Expired = false
+		}
+
+		// Anche la soglia è opzionale (per gli alert percentuali è la variazione, per quelli sul P&L
+		// è la percentuale con segno passata in percent_change)
+		switch alert.Type {
+		case models.AlertTypePercent:
+			if input.PercentChange != nil {
+				if *input.PercentChange <= 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.percent_change_required")})
+					return
+				}
+				alert.PercentChange = *input.PercentChange
+			}
+		case models.AlertTypePositionPnL:
+			if input.PercentChange != nil {
+				if *input.PercentChange == 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.pnl_percent_required")})
+					return
+				}
+				alert.ThresholdPrice = *input.PercentChange
+			}
+		default:
+			if input.ThresholdPrice != nil {
+				if *input.ThresholdPrice <= 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.threshold_required", alert.Type)})
+					return
+				}
+				alert.ThresholdPrice = *input.ThresholdPrice
+			}
+		}
+		if input.Triggered != nil {
+			if *input.Triggered {
+				alert.Triggered = true
+			} else if alert.Triggered {
+				alert.Rearm()
+			}
+		}
 		alert.UpdatedAt = time.Now()
 
-		// Se l'alert viene reimpostato, aggiorna anche il prezzo corrente
+		// Se l'alert viene reimpostato, aggiorna anche il prezzo (o il valore del portafoglio) corrente
 		if !alert.Triggered {
-			price, err := GetPriceUSD(alert.CryptoID)
-			if err == nil {
-				alert.CurrentPrice = price
+			if alert.IsPortfolio() {
+				if value, err := portfolio.Current(c.Request.Context(), db, prices, &alert); err == nil {
+					alert.Observe(value, time.Now().UTC())
+				}
+			} else if price, err := prices.GetPrice(c.Request.Context(), alert.CryptoID, alert.Currency); err == nil {
+				alert.Observe(price, time.Now().UTC())
 			}
 		}
 
-		db.Save(&alert)
+		// Registra nella cronologia le modifiche e l'eventuale reset
+		now := time.Now()
+		var events []models.AlertEvent
+		if event, changed := alert.NewUpdateEvent(&before, now); changed {
+			events = append(events, event)
+		}
+		if before.Triggered && !alert.Triggered {
+			events = append(events, alert.NewEvent(models.AlertEventReset, models.ResetManual, now))
+		}
+
+		if err := alert.SaveWithEvents(db, events...); err != nil {
+			log.Printf("Errore nell'aggiornamento dell'alert: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "alert.update_error", err)})
+			return
+		}
 		c.JSON(http.StatusOK, alert)
 	}
 }
 
-func DeleteAlert(db *gorm.DB) gin.HandlerFunc {
+// GetAlertEvents restituisce la cronologia di un alert, dagli eventi più recenti
+func GetAlertEvents(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, _ := strconv.Atoi(c.Param("id"))
 
 		if err := db.First(&models.Alert{}, id).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Alert non trovato"})
+			c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.alert_not_found")})
+			return
+		}
+
+		query := db.Where("alert_id = ?", id).Order("created_at DESC, id DESC")
+		if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+			query = query.Limit(limit)
+		}
+
+		var events []models.AlertEvent
+		if err := query.Find(&events).Error; err != nil {
+			log.Printf("Errore nel recupero della cronologia dell'alert %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.history_error")})
+			return
+		}
+
+		lang := requestLanguage(c)
+		response := make([]eventResponse, 0, len(events))
+		for _, event := range events {
+			response = append(response, eventResponse{AlertEvent: event, Description: event.Describe(lang)})
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func DeleteAlert(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.Param("id"))
+
+		var alert models.Alert
+		if err := db.First(&alert, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.alert_not_found")})
 			return
 		}
 
-		if err := db.Delete(&models.Alert{}, id).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Errore nella cancellazione"})
+		if err := alert.DeleteWithEvents(db); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.delete_error")})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"message": "Alert eliminato"})
+		c.JSON(http.StatusOK, gin.H{"message": localize(c, "api.alert_deleted")})
 	}
 }
 
-// GetActiveAlerts restituisce tutti gli alert non ancora triggerati
+// GetActiveAlerts restituisce tutti gli alert non ancora triggerati né scaduti
 func GetActiveAlerts(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var alerts []models.Alert
 		userChatIDStr := c.Query("user_chat_id")
-		query := db.Where("triggered = ?", false)
+		query := db.Where("triggered = ? AND expired = ?", false, false)
 
 		if userChatIDStr != "" {
 			userChatID, err := strconv.ParseInt(userChatIDStr, 10, 64)
@@ -166,15 +450,29 @@ func GetActiveAlerts(db *gorm.DB) gin.HandlerFunc {
 
 		if err := query.Find(&alerts).Error; err != nil {
 			log.Printf("Errore nel recupero degli alert attivi: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Errore nel recupero degli alert attivi"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.active_alerts_error")})
 			return
 		}
 
 		if len(alerts) == 0 {
-			c.JSON(http.StatusOK, gin.H{"message": "Nessun alert attivo trovato"})
+			c.JSON(http.StatusOK, gin.H{"message": localize(c, "api.no_active_alerts")})
 			return
 		}
 
 		c.JSON(http.StatusOK, alerts)
 	}
 }
+
+// validateAlertWindow verifica che la scadenza sia futura e successiva all'attivazione
+func validateAlertWindow(activeFrom, expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+	if !expiresAt.After(time.Now()) {
+		return i18n.Errorf("api.expires_in_past")
+	}
+	if activeFrom != nil && !expiresAt.After(*activeFrom) {
+		return i18n.Errorf("api.expires_before_active")
+	}
+	return nil
+}
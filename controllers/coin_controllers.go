@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"crypto-tracker/services/coins"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchCoins restituisce un handler Gin per cercare le criptovalute nel registro locale
+// per ID, simbolo o nome, tollerando nomi parziali ed errori di battitura
+func SearchCoins(registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.query_required")})
+			return
+		}
+
+		limit := coins.DefaultSearchLimit
+		if value := c.Query("limit"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 || n > coins.MaxSearchLimit {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.invalid_limit", coins.MaxSearchLimit)})
+				return
+			}
+			limit = n
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"query":   query,
+			"results": registry.Search(query, limit),
+		})
+	}
+}
@@ -1,101 +1,133 @@
 package controllers
 
 import (
-	"fmt"
-	"log"
+	"crypto-tracker/i18n"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/history"
+	"crypto-tracker/services/pricing"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-resty/resty/v2"
 	"gorm.io/gorm"
 )
 
-// GetPriceUSD prende l'ID della crypto (es. "bitcoin") e restituisce il prezzo in USD
-func GetPriceUSD(coinID string) (float64, error) {
-	client := resty.New()
-
-	url := "https://api.coingecko.com/api/v3/simple/price"
-	log.Printf("[CoinGecko] Inizio richiesta prezzo per: %s", coinID)
-	startTime := time.Now()
-
-	// Ottieni la chiave API dalla variabile d'ambiente o usa un valore di default
-	apiKey := os.Getenv("COINGECKO_API_KEY")
-	if apiKey == "" {
-		log.Println("[CoinGecko] AVVISO: COINGECKO_API_KEY non impostata. L'API potrebbe avere limitazioni.")
-	} else {
-		log.Printf("[CoinGecko] Chiave API trovata (lunghezza: %d caratteri)", len(apiKey))
-	}
+// GetCryptoPriceHandler restituisce un handler Gin per ottenere il prezzo di una criptovaluta
+// L'ID può essere anche un simbolo o un nome (es. "btc"), risolto tramite il registro delle criptovalute.
+func GetCryptoPriceHandler(db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		coinID := registry.Resolve(c.Param("id"))
 
-	// Prepara la richiesta
-	request := client.R().
-		SetQueryParams(map[string]string{
-			"ids":           coinID,
-			"vs_currencies": "usd",
-		}).
-		SetResult(map[string]map[string]float64{})
-
-	// Aggiungi l'header API key se disponibile
-	if apiKey != "" {
-		request.SetHeader("x-cg-demo-api-key", apiKey)
-		log.Println("[CoinGecko] Header API key aggiunto alla richiesta")
-	}
+		if coinID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.crypto_id_missing")})
+			return
+		}
 
-	// Esegui la richiesta GET
-	log.Println("[CoinGecko] Invio richiesta a:", url)
-	resp, err := request.Get(url)
+		currency, err := pricing.ParseCurrency(c.Query("currency"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
 
-	elapsedTime := time.Since(startTime)
-	log.Printf("[CoinGecko] Tempo di risposta: %v", elapsedTime)
+		// Ottieni il prezzo dalla sorgente configurata (eventualmente dalla cache)
+		quote, err := pricing.GetQuote(c.Request.Context(), prices, coinID, currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.price_unavailable")})
+			return
+		}
 
-	if err != nil {
-		log.Printf("[CoinGecko] ERRORE nella richiesta: %v", err)
-		return 0, fmt.Errorf("errore nella richiesta a CoinGecko: %w", err)
+		c.JSON(http.StatusOK, gin.H{
+			"id":          coinID,
+			"price":       quote.Price,
+			"currency":    quote.Currency,
+			"timestamp":   quote.AsOf,
+			"age_seconds": int(quote.Age().Seconds()),
+		})
 	}
+}
 
-	log.Printf("[CoinGecko] Stato risposta: %s", resp.Status())
+// maxHistoryRange è l'ampiezza massima del periodo richiedibile allo storico dei prezzi
+const maxHistoryRange = 366 * 24 * time.Hour
 
-	if resp.IsError() {
-		log.Printf("[CoinGecko] ERRORE risposta non valida: %s, Body: %s", resp.Status(), resp.String())
-		return 0, fmt.Errorf("risposta non valida da CoinGecko: %s", resp.Status())
-	}
+// GetPriceHistoryHandler restituisce un handler Gin per lo storico dei prezzi di una criptovaluta.
+// Senza interval restituisce i singoli punti, altrimenti le candele OHLC dell'intervallo indicato.
+func GetPriceHistoryHandler(store *history.Store, registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		coinID := registry.Resolve(c.Param("id"))
+
+		currency, err := pricing.ParseCurrency(c.Query("currency"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
 
-	// Parsing del risultato
-	result := resp.Result().(*map[string]map[string]float64)
-	log.Printf("[CoinGecko] Risposta JSON ricevuta: %v", *result)
+		// Il periodo predefinito sono le ultime 24 ore
+		to := time.Now().UTC()
+		if value := c.Query("to"); value != "" {
+			if to, err = parseTimeParam(value); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.invalid_param", "to", err)})
+				return
+			}
+		}
+		from := to.Add(-24 * time.Hour)
+		if value := c.Query("from"); value != "" {
+			if from, err = parseTimeParam(value); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.invalid_param", "from", err)})
+				return
+			}
+		}
 
-	price, ok := (*result)[coinID]["usd"]
-	if !ok {
-		log.Printf("[CoinGecko] ERRORE prezzo non trovato per %s", coinID)
-		return 0, fmt.Errorf("prezzo non trovato per %s", coinID)
-	}
+		if !from.Before(to) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.from_after_to")})
+			return
+		}
+		if to.Sub(from) > maxHistoryRange {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.range_too_long")})
+			return
+		}
 
-	log.Printf("[CoinGecko] Prezzo ottenuto con successo per %s: $%.2f USD", coinID, price)
-	return price, nil
-}
+		samples, err := store.Samples(coinID, currency, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.price_history_error")})
+			return
+		}
 
-// GetCryptoPriceHandler restituisce un handler Gin per ottenere il prezzo di una criptovaluta
-func GetCryptoPriceHandler(db *gorm.DB) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		coinID := c.Param("id")
+		response := gin.H{
+			"id":       coinID,
+			"currency": currency,
+			"from":     from,
+			"to":       to,
+		}
 
-		if coinID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "ID criptovaluta non fornito"})
+		interval := c.Query("interval")
+		if interval == "" {
+			response["points"] = history.Points(samples)
+			c.JSON(http.StatusOK, response)
 			return
 		}
 
-		// Ottieni il prezzo usando la funzione GetPriceUSD
-		price, err := GetPriceUSD(coinID)
+		d, err := history.ParseInterval(interval)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Impossibile ottenere il prezzo. Verifica che l'ID della criptovaluta sia corretto."})
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"id":        coinID,
-			"price":     price,
-			"timestamp": time.Now(),
-		})
+		response["interval"] = interval
+		response["candles"] = history.Candles(samples, d)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// parseTimeParam interpreta una data in formato RFC 3339 o come timestamp Unix in secondi
+func parseTimeParam(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, i18n.Errorf("api.invalid_time_format")
 	}
+	return t.UTC(), nil
 }
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"crypto-tracker/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLanguage restituisce la lingua della richiesta in base all'header Accept-Language
+func requestLanguage(c *gin.Context) string {
+	return i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// localize restituisce il messaggio del catalogo nella lingua della richiesta
+func localize(c *gin.Context, key string, args ...any) string {
+	return i18n.T(requestLanguage(c), key, args...)
+}
+
+// localizeError restituisce il messaggio di un errore nella lingua della richiesta
+func localizeError(c *gin.Context, err error) string {
+	return i18n.Message(requestLanguage(c), err)
+}
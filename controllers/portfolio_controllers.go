@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"crypto-tracker/models"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/portfolio"
+	"crypto-tracker/services/pricing"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetPortfolio restituisce le posizioni di un utente valutate ai prezzi correnti
+func GetPortfolio(db *gorm.DB, prices pricing.PriceProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userChatID, err := strconv.ParseInt(c.Query("user_chat_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.user_chat_id_required")})
+			return
+		}
+
+		holdings, err := portfolio.Holdings(db, userChatID)
+		if err != nil {
+			log.Printf("Errore nel recupero del portafoglio: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.portfolio_error")})
+			return
+		}
+
+		summary, err := portfolio.Valuate(c.Request.Context(), prices, holdings)
+		if err != nil {
+			log.Printf("Errore nella valutazione del portafoglio: %v", err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": localize(c, "api.portfolio_prices_error")})
+			return
+		}
+
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// GetTransactions restituisce le transazioni di un utente, dalle più recenti
+func GetTransactions(db *gorm.DB, registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userChatID, err := strconv.ParseInt(c.Query("user_chat_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.user_chat_id_required")})
+			return
+		}
+
+		query := db.Where("user_chat_id = ?", userChatID)
+		if cryptoID := c.Query("crypto_id"); cryptoID != "" {
+			query = query.Where("cryptocurrency_id = ?", registry.Resolve(cryptoID))
+		}
+
+		var transactions []models.Transaction
+		if err := query.Order("executed_at DESC, id DESC").Find(&transactions).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.transactions_error")})
+			return
+		}
+
+		c.JSON(http.StatusOK, transactions)
+	}
+}
+
+// CreateTransaction registra un acquisto o una vendita; senza prezzo usa quello di mercato
+func CreateTransaction(db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			UserChatID int64      `json:"user_chat_id" binding:"required"`
+			CryptoID   string     `json:"crypto_id" binding:"required"`
+			Type       string     `json:"type" binding:"required"`
+			Quantity   float64    `json:"quantity" binding:"required"`
+			Price      float64    `json:"price"`
+			Currency   string     `json:"currency"`
+			ExecutedAt *time.Time `json:"executed_at"`
+		}
+
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		txType, err := models.ParseTransactionType(input.Type)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		currency, err := pricing.ParseCurrency(input.Currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		if input.Quantity <= 0 || input.Price < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.invalid_trade")})
+			return
+		}
+
+		cryptoID := registry.Resolve(input.CryptoID)
+		price := input.Price
+		if price == 0 {
+			// Senza prezzo esplicito la transazione avviene al prezzo di mercato, che verifica anche l'ID
+			if price, err = prices.GetPrice(c.Request.Context(), cryptoID, currency); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.price_unavailable")})
+				return
+			}
+		}
+
+		tx := models.Transaction{
+			UserChatID: input.UserChatID,
+			CryptoID:   cryptoID,
+			Type:       txType,
+			Quantity:   input.Quantity,
+			Price:      price,
+			Currency:   currency,
+		}
+		if input.ExecutedAt != nil {
+			tx.ExecutedAt = input.ExecutedAt.UTC()
+		}
+
+		tx, holding, err := portfolio.Record(db, tx)
+		switch {
+		case errors.Is(err, models.ErrInsufficientQuantity), errors.Is(err, portfolio.ErrCurrencyMismatch):
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		case err != nil:
+			log.Printf("Errore nella registrazione della transazione: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.transaction_error")})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"transaction": tx, "holding": holding})
+	}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/watchlist"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetWatchlist restituisce la watchlist di un utente con prezzi e variazioni nelle 24 ore
+func GetWatchlist(db *gorm.DB, prices pricing.PriceProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userChatID, err := strconv.ParseInt(c.Query("user_chat_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.user_chat_id_required")})
+			return
+		}
+
+		currency, err := pricing.ParseCurrency(c.Query("currency"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		items, err := watchlist.Items(db, userChatID)
+		if err != nil {
+			log.Printf("Errore nel recupero della watchlist: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.watchlist_error")})
+			return
+		}
+
+		entries, err := watchlist.Snapshot(c.Request.Context(), prices, watchlist.CoinIDs(items), currency)
+		if err != nil {
+			log.Printf("Errore nella quotazione della watchlist: %v", err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": localize(c, "api.watchlist_prices_error")})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"user_chat_id": userChatID, "currency": currency, "items": entries})
+	}
+}
+
+// AddToWatchlist aggiunge una o più criptovalute alla watchlist di un utente
+func AddToWatchlist(db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			UserChatID int64    `json:"user_chat_id" binding:"required"`
+			CryptoID   string   `json:"crypto_id"`
+			CryptoIDs  []string `json:"crypto_ids"`
+		}
+
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		}
+
+		coinIDs := normalizeCoinIDs(registry, append(input.CryptoIDs, input.CryptoID))
+		if len(coinIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.crypto_ids_required")})
+			return
+		}
+
+		// Verifica con un'unica richiesta che tutti gli ID abbiano un prezzo
+		quotes, err := pricing.GetQuotes(c.Request.Context(), prices, coinIDs, pricing.DefaultCurrency)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": localize(c, "api.coins_verify_error")})
+			return
+		}
+		var unknown []string
+		for _, coinID := range coinIDs {
+			if _, ok := quotes[coinID]; !ok {
+				unknown = append(unknown, coinID)
+			}
+		}
+		if len(unknown) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.coins_not_found", strings.Join(unknown, ", "))})
+			return
+		}
+
+		added, err := watchlist.Add(db, input.UserChatID, coinIDs)
+		switch {
+		case errors.Is(err, watchlist.ErrFull):
+			c.JSON(http.StatusBadRequest, gin.H{"error": localizeError(c, err)})
+			return
+		case err != nil:
+			log.Printf("Errore nell'aggiornamento della watchlist: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.watchlist_update_error")})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"added": added})
+	}
+}
+
+// RemoveFromWatchlist rimuove una criptovaluta dalla watchlist di un utente
+func RemoveFromWatchlist(db *gorm.DB, registry *coins.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userChatID, err := strconv.ParseInt(c.Query("user_chat_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.user_chat_id_required")})
+			return
+		}
+
+		removed, err := watchlist.Remove(db, userChatID, []string{registry.Resolve(c.Param("id"))})
+		if err != nil {
+			log.Printf("Errore nell'aggiornamento della watchlist: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": localize(c, "api.watchlist_update_error")})
+			return
+		}
+		if removed == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.not_in_watchlist")})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": localize(c, "api.watchlist_removed")})
+	}
+}
+
+// normalizeCoinIDs risolve gli ID (o simboli e nomi) tramite il registro, scartando quelli vuoti e i duplicati
+func normalizeCoinIDs(registry *coins.Registry, values []string) []string {
+	var coinIDs []string
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if coinID := registry.Resolve(value); !slices.Contains(coinIDs, coinID) {
+			coinIDs = append(coinIDs, coinID)
+		}
+	}
+	return coinIDs
+}
@@ -0,0 +1,352 @@
+package i18n
+
+// english contiene i messaggi in inglese
+var english = map[string]string{
+	// Messaggi generali del bot
+	"bot.send_command":      "Send a command, for example /help or /price bitcoin",
+	"bot.cancelled":         "Operation cancelled.",
+	"bot.nothing_to_cancel": "Nothing to cancel.",
+	"bot.unknown_command":   "Unknown command. Use /help to see the available commands.",
+	"help.text": `
+Available commands:
+/price <crypto_id> [currency] - Gets the current price (e.g. /price bitcoin or /price bitcoin eur)
+/search <text> - Searches a cryptocurrency by name, symbol or ID, even with typos (e.g. /search etherium)
+/range <crypto_id> [currency] - Shows low, high and change over the last 24 hours (only for monitored cryptocurrencies)
+/chart <crypto_id> [24h|7d|30d] [currency] [--line] - Sends the price chart with your alert thresholds (e.g. /chart bitcoin 7d)
+/create_alert <crypto_id> [>|<|<>] <threshold_price> [currency] - Creates a new alert (e.g. /create_alert bitcoin 30000 eur or /create_alert ethereum < 2000)
+   By default the alert fires when the price crosses the threshold; add --level to fire as soon as the price is past the threshold
+   With --for 7d the alert expires after 7 days, with --after 2h it is only checked starting 2 hours after creation
+   Options for recurring alerts (also for /percent_alert): --repeat to rearm it after firing, --cooldown 1h minimum wait between two fires, --band 2 percent distance from the threshold for rearming, --max 5 maximum number of fires
+/percent_alert <crypto_id> <[+|-]percent> [currency] [--24h] - Percent change alert (e.g. /percent_alert solana 8 for ±8% from now, /percent_alert bitcoin -5 --24h compared to 24 hours earlier)
+/update_alert <id> [>|<|<>] <threshold_price> - Updates an existing alert (e.g. /update_alert 1 32000; for percent alerts give the new percentage)
+/alerts - Shows all alerts
+/active_alerts - Shows only active (not triggered) alerts
+/alert <id> - Shows the details of a specific alert
+/delete_alert <id> - Deletes a specific alert
+/history <id> - Shows the history of an alert (fires, resets, edits and expiry)
+/buy <crypto_id> <quantity> [price] [currency] - Records a purchase in the portfolio (without a price the market price is used)
+/sell <crypto_id> <quantity> [price] [currency] - Records a sale in the portfolio
+/portfolio - Shows value, allocation and P&L of your positions
+/portfolio_alert [>|<|<>] <value> [currency] - Alert on the total portfolio value (e.g. /portfolio_alert < 10000 eur)
+/pnl_alert <crypto_id> <[+|-]percent> - Alert on the unrealized P&L of a position (e.g. /pnl_alert bitcoin 30 for take profit, /pnl_alert bitcoin -20 for stop loss)
+/watch <crypto_id> [crypto_id...] - Adds cryptocurrencies to your watchlist (e.g. /watch bitcoin ethereum solana)
+/unwatch <crypto_id> [crypto_id...] - Removes cryptocurrencies from the watchlist
+/watchlist [currency] - Shows prices and 24-hour changes of the whole watchlist in a single message
+/digest daily|weekly [day] <HH:MM> [currency] - Receive a periodic summary of watchlist and alerts (e.g. /digest daily 08:00, /digest weekly mon 09:30, /digest off to unsubscribe)
+/new_alert [crypto_id] - Creates an alert step by step: cryptocurrency, direction, threshold and expiry
+/cancel - Cancels the current operation (e.g. /new_alert or a threshold edit started with the ✏️ button)
+/language [it|en] - Shows or changes the language of the bot's messages (e.g. /language it)
+/timezone [time_zone] - Shows or changes the time zone of the dates shown by the bot (e.g. /timezone Europe/London)
+/help - Shows this message
+
+Alert lists and notifications include buttons to delete, reactivate, snooze for an hour or edit the threshold of an alert.
+`,
+	"common.error":             "Error: %v",
+	"common.price_unavailable": "Error: Unable to get the price for '%s'. Check that the ID is correct.",
+	"common.invalid_id":        "Invalid ID. Use a positive integer.",
+	"common.invalid_price":     "Invalid price. Use a decimal number.",
+	"common.updated_at":        "Updated on: %s",
+
+	// Lingua
+	"language.current":    "🌐 Current language: %s\n\nAvailable languages:\n%s\n\nUse /language <code> to change it, e.g. /language it",
+	"language.invalid":    "Unsupported language: %s (available languages: %s)",
+	"language.save_error": "Error saving the language: %v",
+	"language.updated":    "🌐 Language set: %s",
+
+	// Fuso orario
+	"timezone.current":            "🕐 Current time zone: %s (local time %s)\n\nUse /timezone <time_zone> to change it, e.g. /timezone Europe/London",
+	"timezone.usage":              "Format: /timezone <time_zone>, with the IANA time zone name (e.g. Europe/Rome, Europe/London, America/New_York or UTC)",
+	"timezone.save_error":         "Error saving the time zone: %v",
+	"timezone.updated":            "🕐 Time zone set: %s (local time %s)",
+	"timezone.digest_rescheduled": "The digest will follow the new time zone, next delivery: %s",
+
+	// Prezzi, storico e grafici
+	"price.usage":            "Specify the cryptocurrency ID. Example: /price bitcoin",
+	"price.freshness":        "🕒 Updated at %s, %v ago",
+	"range.usage":            "Specify the cryptocurrency ID. Example: /range bitcoin",
+	"range.error":            "Error loading the price history: %v",
+	"range.no_data":          "No price history for %s in %s in the last 24 hours. History is only recorded for cryptocurrencies with active alerts.",
+	"range.summary":          "📊 %s in the last 24 hours (%s)\n\nOpen: %s\nHigh: %s\nLow: %s\nLast: %s\nChange: %+.2f%%\nRange: %.2f%%\n\nFrom %s to %s, %d samples",
+	"chart.usage":            "Format: /chart <crypto_id> [24h|7d|30d] [currency] [--line]",
+	"chart.invalid_argument": "Error: %v\nThe available periods are 24h, 7d and 30d.",
+	"chart.history_error":    "Error: unable to get the price history of '%s': %v",
+	"chart.render_error":     "Error creating the chart: %v",
+	"chart.caption":          "📈 %s, %s: %+.2f%%\nMin %s | Max %s | Last %s",
+	"chart.threshold_lines":  "Dashed lines: thresholds of your %d active alerts",
+	"chart.partial_history":  "ℹ️ History only available since %s",
+	"chart.period_24h":       "last 24 hours",
+	"chart.period_7d":        "last 7 days",
+	"chart.period_30d":       "last 30 days",
+
+	// Ricerca
+	"search.usage":        "Specify the name, symbol or ID to search for. Example: /search bitcoin",
+	"search.unavailable":  "The cryptocurrency list is not available yet, try again later.",
+	"search.no_results":   "No cryptocurrency found for '%s'.",
+	"search.header":       "🔎 Results for '%s':\n\n",
+	"search.rank":         " - #%d by market cap",
+	"search.hint":         "\nUse the ID in commands, e.g. /price %s",
+	"search.did_you_mean": "Did you mean: %s?",
+
+	// Alert
+	"alert.create_error":                 "Error creating the alert: %v",
+	"alert.created":                      "✅ Alert created! ID: %d\nCrypto: %s\nThreshold: %s\nCurrent price: %s\nCreated on: %s",
+	"alert.percent_created":              "✅ Percent alert created! ID: %d\nCrypto: %s\nCondition: %s\nCurrent price: %s (%+.2f%%)\nCreated on: %s",
+	"alert.portfolio_created":            "✅ Portfolio alert created! ID: %d\n%s\nThreshold: %s\n%s\nCreated on: %s",
+	"alert.update_usage":                 "Format: /update_alert <id> [>|<|<>] <threshold_price>",
+	"alert.not_found_edit":               "Alert not found or you are not allowed to edit it.",
+	"alert.not_found_view":               "Alert not found or you are not allowed to view it.",
+	"alert.not_found_delete":             "Alert not found or you are not allowed to delete it.",
+	"alert.update_price_warning":         "Warning: Unable to get the updated price: %v",
+	"alert.update_error":                 "Error updating the alert: %v",
+	"alert.status_reset":                 "⚠️ The status has been reset from triggered to active!",
+	"alert.updated":                      "✅ Alert updated! ID: %d\n%s\nNew threshold: %s\n%s\nStatus: %s%s",
+	"alert.list_error":                   "Error loading the alerts: %v",
+	"alert.list_empty":                   "You have no saved alerts.",
+	"alert.list_header":                  "📊 Your alerts:\n\n",
+	"alert.list_item":                    "ID: %d | %s\n%s\nThreshold: %s\n%s\n%s%s\n",
+	"alert.active_list_error":            "Error loading the active alerts: %v",
+	"alert.active_list_empty":            "You have no active alerts.",
+	"alert.active_list_header":           "⚡ Your active alerts:\n\n",
+	"alert.active_list_item":             "ID: %d\n%s\nThreshold: %s\n%s\n%s\n\n",
+	"alert.triggered_at":                 "Triggered on: %s",
+	"alert.created_at":                   "Created on: %s",
+	"alert.id_required":                  "Specify the alert ID. Example: %s 1",
+	"alert.detail":                       "🔔 Alert #%d\n\n%s\nThreshold: %s\n%s\nStatus: %s\n%s",
+	"alert.delete_error":                 "Error deleting the alert: %v",
+	"alert.deleted":                      "🗑️ Alert #%d deleted successfully.",
+	"alert.warning_already_met_level":    "The condition is already met by the current price: the alert will fire at the next check.",
+	"alert.warning_already_met_crossing": "The condition is already met by the current price: the alert will only fire when the price crosses the threshold again.",
+	"status.expired":                     "⌛ Expired",
+	"status.triggered":                   "✅ Triggered",
+	"status.scheduled":                   "🕒 Scheduled",
+	"status.waiting":                     "⏳ Waiting",
+
+	// Cronologia degli alert
+	"history.error":              "Error loading the history: %v",
+	"history.empty":              "Alert #%d has no recorded events yet.",
+	"history.header":             "📜 History of alert #%d (%s, %s)\n\n",
+	"history.item":               "%s - %s at %s\n",
+	"event.trigger":              "🚨 Fired",
+	"event.reset":                "🔄 Rearmed",
+	"event.update":               "✏️ Edited",
+	"event.expiry":               "⌛ Expired",
+	"event.reason.manual":        "manual reset",
+	"event.reason.automatic":     "automatic rearm",
+	"event.change":               "%s %s → %s",
+	"event.value_none":           "none",
+	"event.value_yes":            "yes",
+	"event.value_no":             "no",
+	"event.field.threshold":      "threshold",
+	"event.field.percent_change": "change",
+	"event.field.direction":      "direction",
+	"event.field.trigger_mode":   "mode",
+	"event.field.recurring":      "recurring",
+	"event.field.cooldown":       "cooldown (minutes)",
+	"event.field.hysteresis":     "hysteresis (%)",
+	"event.field.max_fires":      "max fires",
+	"event.field.active_from":    "active from",
+	"event.field.expires_at":     "expiry",
+
+	// Notifiche
+	"notification.triggered": "🚨 ALERT TRIGGERED! 🚨\n\nID: %d\n%s\nThreshold: %s\n%s\nDate: %s",
+	"notification.change":    "Change: %+.2f%% (reference %s)",
+	"notification.expired":   "⌛ Alert expired\n\nID: %d\n%s\nThreshold: %s\n%s\n\nThe alert never fired and will no longer be checked.",
+
+	// Argomenti dei comandi
+	"args.option_without_value":  "Option %s has no value",
+	"args.invalid_band":          "Invalid hysteresis band. Use a percentage, e.g. --band 2",
+	"args.invalid_max":           "Invalid maximum number of fires, e.g. --max 5",
+	"args.unknown_option":        "Unknown option: %s",
+	"args.invalid_duration":      "Invalid duration: %s (use for example 30m, 6h, 7d or 2w)",
+	"args.invalid_cooldown":      "Invalid cooldown: %s (use at least 1 minute, e.g. 30m or 1h)",
+	"args.rolling_percent_only":  "The --24h option only applies to /percent_alert",
+	"args.create_alert_usage":    "Format: /create_alert <crypto_id> [>|<|<>] <threshold_price> [currency] %s",
+	"args.threshold_required":    "Specify the threshold price. Example: /create_alert bitcoin < 60000",
+	"args.invalid_threshold":     "Invalid threshold. Use a positive number.",
+	"args.percent_alert_usage":   "Format: /percent_alert <crypto_id> <[+|-]percent> [currency] [--24h] %s",
+	"args.invalid_percent":       "Invalid percentage. Use a positive number, e.g. 8, +8 or -8.",
+	"args.portfolio_alert_usage": "Format: /portfolio_alert [>|<|<>] <value> [currency] %s",
+	"args.invalid_value":         "Invalid value. Use a positive number.",
+	"args.pnl_alert_usage":       "Format: /pnl_alert <crypto_id> <[+|-]percent> %s",
+	"args.invalid_pnl_percent":   "Invalid percentage. Use a non-zero number, e.g. 30 or -20.",
+
+	// Formattazione degli alert
+	"format.reference_from":    "from %s",
+	"format.reference_24h":     "over 24h",
+	"format.subject_portfolio": "portfolio %s",
+	"format.subject_position":  "position %s",
+	"format.label_portfolio":   "Portfolio: %s",
+	"format.label_position":    "Position: %s",
+	"format.label_crypto":      "Crypto: %s",
+	"format.current_portfolio": "Portfolio value: %s",
+	"format.current_position":  "Position P&L: %s",
+	"format.current_price":     "Current price: %s",
+	"format.repeat_fires":      "%s fires",
+	"format.repeat_cooldown":   "cooldown %d min",
+	"format.repeat_band":       "band %.2f%%",
+	"format.repeat":            "🔁 Recurring: %s",
+	"format.active_from":       "Active from: %s",
+	"format.expires_at":        "Expires on: %s",
+
+	// Pulsanti e azioni rapide
+	"button.delete":                  "🗑 Delete",
+	"button.reset":                   "🔄 Reset",
+	"button.snooze":                  "💤 1h",
+	"button.edit":                    "✏️ Threshold",
+	"callback.unknown_action":        "Unknown action.",
+	"callback.deleted":               "🗑️ Alert #%d deleted",
+	"callback.already_active":        "Alert #%d is already active.",
+	"callback.reset":                 "🔄 Alert #%d reactivated",
+	"callback.expires_before_snooze": "Alert #%d expires before the end of the snooze.",
+	"callback.snoozed":               "💤 Alert #%d snoozed until %s",
+	"callback.edit_prompt":           "✏️ Send the new threshold for alert #%d (%s, %s), e.g. 32000 or < 30000.\nUse /cancel to abort.",
+
+	// Creazione guidata degli alert
+	"conversation.start":             "🆕 Guided alert creation (use /cancel to abort).\n\nWhich cryptocurrency? Pick a suggestion or type the CoinGecko ID (e.g. bitcoin).",
+	"conversation.coin_not_found":    "I can't find the cryptocurrency '%s'. Check the CoinGecko ID and try again, or use /cancel.",
+	"conversation.ask_direction":     "💰 %s: %s\n\nWhen do you want to be notified?",
+	"conversation.button_above":      "⬆️ Above the threshold",
+	"conversation.button_below":      "⬇️ Below the threshold",
+	"conversation.button_cross":      "↕️ Crossing",
+	"conversation.word_above":        "above",
+	"conversation.word_below":        "below",
+	"conversation.word_cross":        "cross",
+	"conversation.invalid_direction": "Invalid choice. Use one of the buttons or >, < or <>.",
+	"conversation.ask_threshold":     "Send the threshold price in %s (current price %s).",
+	"conversation.invalid_threshold": "Invalid price. Send a positive number, e.g. 65000.",
+	"conversation.ask_expiry":        "How long should the alert stay active?",
+	"conversation.button_no_expiry":  "No expiry",
+	"conversation.timeout":           "⌛ Operation cancelled due to inactivity. Start again whenever you like, for example with /new_alert.",
+
+	// Portafoglio
+	"trade.usage":                            "Format: /%s <crypto_id> <quantity> [price] [currency]\nExample: /%s bitcoin 0.25 60000",
+	"trade.invalid_quantity":                 "Invalid quantity. Use a positive number.",
+	"trade.invalid_price":                    "Invalid price. Use a positive number.",
+	"trade.error":                            "Error recording the transaction: %v",
+	"trade.buy_recorded":                     "🟢 Purchase recorded",
+	"trade.sell_recorded":                    "🔴 Sale recorded",
+	"trade.recorded":                         "%s\n\n%g %s at %s (total %s)\nPosition: %g %s, average cost %s",
+	"trade.realized_pnl":                     "Total realized P&L: %s",
+	"portfolio.load_error":                   "Error loading the portfolio: %v",
+	"portfolio.empty":                        "Your portfolio is empty. Record a purchase with /buy <crypto_id> <quantity> [price].",
+	"portfolio.prices_error":                 "Error: unable to get the prices to value the portfolio: %v",
+	"portfolio.header":                       "💼 Your portfolio\n\n",
+	"portfolio.price_unavailable":            "Price unavailable\n\n",
+	"portfolio.position":                     "Value: %s (%.1f%%)\nP&L: %s (%+.2f%%)\n\n",
+	"portfolio.total":                        "📊 Total %s: %s\nUnrealized P&L: %s (%+.2f%%)\nRealized P&L: %s\n",
+	"portfolio.valuation_error":              "Error: unable to value the portfolio: %v",
+	"portfolio.insufficient_quantity":        "insufficient quantity",
+	"portfolio.insufficient_quantity_detail": "%v: you hold %g %s",
+	"portfolio.currency_mismatch":            "currency differs from the position's",
+	"portfolio.currency_mismatch_detail":     "%v: the position in %s is recorded in %s",
+	"portfolio.no_position":                  "no open position in %s",
+	"portfolio.no_valued_position":           "no position that can be valued in %s",
+	"portfolio.invalid_trade":                "quantity and price must be positive",
+
+	// Watchlist
+	"watchlist.watch_usage":       "Specify one or more cryptocurrencies. Example: /watch bitcoin ethereum solana",
+	"watchlist.unwatch_usage":     "Specify one or more cryptocurrencies. Example: /unwatch solana",
+	"watchlist.verify_error":      "Error: unable to verify the cryptocurrencies: %v",
+	"watchlist.update_error":      "Error updating the watchlist: %v",
+	"watchlist.added":             "👀 Added to the watchlist: %s\n",
+	"watchlist.already_present":   "Already present: %s\n",
+	"watchlist.not_found":         "⚠️ Not found: %s. Check that the IDs are correct.\n",
+	"watchlist.hint":              "\nUse /watchlist to see the prices.",
+	"watchlist.none_removed":      "None of the given cryptocurrencies is in your watchlist.",
+	"watchlist.removed":           "🗑️ Removed %d cryptocurrencies from the watchlist.",
+	"watchlist.load_error":        "Error loading the watchlist: %v",
+	"watchlist.empty":             "Your watchlist is empty. Add cryptocurrencies with /watch <crypto_id>, e.g. /watch bitcoin ethereum",
+	"watchlist.prices_error":      "Error: unable to get the watchlist prices: %v",
+	"watchlist.header":            "👀 Your watchlist (%s)\n\n",
+	"watchlist.price_unavailable": "%s: price unavailable\n",
+	"watchlist.full":              "the watchlist can hold at most %d cryptocurrencies",
+
+	// Digest
+	"digest.usage":            "Format:\n/digest daily 08:00 [currency] - summary every day\n/digest weekly mon 08:00 [currency] - summary every week\n/digest now - send the summary now\n/digest off - unsubscribe",
+	"digest.load_error":       "Error loading the digest: %v",
+	"digest.not_subscribed":   "You are not subscribed to any digest.",
+	"digest.status":           "📬 Digest %s\nNext delivery: %s\n\n%s",
+	"digest.delete_error":     "Error deleting the digest: %v",
+	"digest.unsubscribed":     "🔕 Digest subscription cancelled.",
+	"digest.build_error":      "Error preparing the digest: %v",
+	"digest.save_error":       "Error saving the digest: %v",
+	"digest.saved":            "📬 Digest set: %s\nNext delivery: %s\n\nThe summary includes your watchlist, active alerts and alerts fired since the previous delivery.",
+	"digest.weekday_required": "Specify the day of the week, e.g. /digest weekly mon 08:00",
+	"digest.invalid_weekday":  "Invalid day: %q (use mon, tue, wed, thu, fri, sat or sun)",
+	"digest.time_required":    "Specify the delivery time, e.g. 08:00",
+	"digest.every_day":        "every day",
+	"digest.every_weekday":    "every %s",
+	"digest.schedule":         "%s at %02d:%02d, prices in %s",
+	"digest.header":           "📬 Your summary for %s\n",
+	"digest.empty":            "\nNothing to report: add cryptocurrencies with /watch or create an alert with /create_alert.",
+	"digest.fired_header":     "\n🚨 Alerts fired since %s: %d\n",
+	"digest.fired_item":       "#%d %s %s - %s at %s\n",
+	"digest.active_header":    "\n⏳ Active alerts: %d\n",
+	"digest.active_item":      "#%d %s %s (now %s)\n",
+	"weekday.0":               "Sunday",
+	"weekday.1":               "Monday",
+	"weekday.2":               "Tuesday",
+	"weekday.3":               "Wednesday",
+	"weekday.4":               "Thursday",
+	"weekday.5":               "Friday",
+	"weekday.6":               "Saturday",
+
+	// Validazione dei valori
+	"validation.invalid_direction":        "invalid direction: %q (use above, below or cross)",
+	"validation.invalid_trigger_mode":     "invalid mode: %q (use crossing or level)",
+	"validation.invalid_alert_type":       "invalid alert type: %q (use price, percent, portfolio_value or position_pnl)",
+	"validation.invalid_baseline":         "invalid baseline: %q (use fixed or 24h)",
+	"validation.change24h_unavailable":    "24-hour change not available for %s",
+	"validation.invalid_frequency":        "invalid frequency: %q (use daily or weekly)",
+	"validation.invalid_clock":            "invalid time: %q (use the HH:MM format, e.g. 08:00)",
+	"validation.invalid_transaction_type": "invalid transaction type: %q (use buy or sell)",
+	"validation.unsupported_currency":     "unsupported currency: %s (available currencies: %s)",
+	"validation.invalid_timezone":         "invalid time zone: %q",
+	"validation.invalid_chat_status":      "invalid chat status: %q (use active or blocked)",
+	"validation.invalid_interval":         "invalid interval: %q (use for example 15m, 1h or 1d)",
+
+	// Errori delle API REST
+	"api.crypto_id_required":        "crypto_id is required",
+	"api.crypto_id_missing":         "Cryptocurrency ID not provided",
+	"api.crypto_ids_required":       "crypto_id or crypto_ids is required",
+	"api.user_chat_id_required":     "user_chat_id is required",
+	"api.threshold_required":        "threshold_price is required and must be positive for alerts of type %s",
+	"api.percent_change_required":   "percent_change is required and must be positive for alerts of type percent",
+	"api.pnl_percent_required":      "percent_change is required for alerts of type position_pnl (e.g. 30 or -20)",
+	"api.negative_recurrence":       "cooldown_minutes, hysteresis_percent and max_fires cannot be negative",
+	"api.expires_in_past":           "expires_at must be a future date",
+	"api.expires_before_active":     "expires_at must be after active_from",
+	"api.portfolio_valuation_error": "Unable to value the portfolio: %v",
+	"api.price_unavailable":         "Unable to get the price for the given cryptocurrency. Check that the ID is correct.",
+	"api.alerts_error":              "Error loading the alerts",
+	"api.active_alerts_error":       "Error loading the active alerts",
+	"api.no_alerts":                 "No alerts found",
+	"api.no_active_alerts":          "No active alerts found",
+	"api.alert_not_found":           "Alert not found",
+	"api.history_error":             "Error loading the alert history",
+	"api.delete_error":              "Error deleting the alert",
+	"api.alert_deleted":             "Alert deleted",
+	"api.query_required":            "Parameter q is required",
+	"api.invalid_limit":             "limit must be a number between 1 and %d",
+	"api.invalid_param":             "Invalid %s parameter: %v",
+	"api.invalid_time_format":       "use the RFC 3339 format (e.g. 2026-01-02T15:04:05Z) or a Unix timestamp",
+	"api.from_after_to":             "from must be before to",
+	"api.range_too_long":            "The requested period cannot exceed one year",
+	"api.price_history_error":       "Error loading the price history",
+	"api.portfolio_error":           "Error loading the portfolio",
+	"api.portfolio_prices_error":    "Unable to get the prices to value the portfolio",
+	"api.transactions_error":        "Error loading the transactions",
+	"api.invalid_trade":             "quantity must be positive and price cannot be negative",
+	"api.transaction_error":         "Error recording the transaction",
+	"api.watchlist_error":           "Error loading the watchlist",
+	"api.watchlist_prices_error":    "Unable to get the watchlist prices",
+	"api.coins_verify_error":        "Unable to verify the given cryptocurrencies",
+	"api.coins_not_found":           "Cryptocurrencies not found: %s. Check that the IDs are correct.",
+	"api.watchlist_update_error":    "Error updating the watchlist",
+	"api.not_in_watchlist":          "Cryptocurrency not in the watchlist",
+	"api.watchlist_removed":         "Cryptocurrency removed from the watchlist",
+	"api.unauthorized":              "Missing or invalid admin token",
+	"api.invalid_offset":            "offset must be a non-negative number",
+	"api.chats_error":               "Error retrieving the chats",
+	"api.chat_not_found":            "Chat not found",
+}
@@ -0,0 +1,156 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Lingue supportate
+const (
+	Italian = "it"
+	English = "en"
+)
+
+// DefaultLanguage è la lingua usata quando quella richiesta non è supportata
+const DefaultLanguage = Italian
+
+// catalogs contiene i messaggi di ciascuna lingua, indicizzati per chiave
+var catalogs = map[string]map[string]string{
+	Italian: italian,
+	English: english,
+}
+
+// languageNames contiene il nome di ciascuna lingua, nella lingua stessa
+var languageNames = map[string]string{
+	Italian: "Italiano 🇮🇹",
+	English: "English 🇬🇧",
+}
+
+// Supported restituisce le lingue supportate
+func Supported() []string {
+	return []string{Italian, English}
+}
+
+// Name restituisce il nome di una lingua nella lingua stessa (es. "English 🇬🇧")
+func Name(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// Parse converte un codice di lingua (es. "en", "en-US" o "it_IT") nella lingua supportata corrispondente
+func Parse(code string) (string, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if base, _, found := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-"); found {
+		code = base
+	}
+
+	if slices.Contains(Supported(), code) {
+		return code, true
+	}
+	return "", false
+}
+
+// Match restituisce la lingua supportata corrispondente al codice indicato, oppure quella predefinita
+func Match(code string) string {
+	if lang, ok := Parse(code); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// FromAcceptLanguage sceglie la lingua in base all'header HTTP Accept-Language
+// (es. "en-US,en;q=0.9,it;q=0.8"), rispettando le preferenze indicate con q
+func FromAcceptLanguage(header string) string {
+	best, bestQuality := DefaultLanguage, 0.0
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+
+		// A parità di preferenza vale l'ordine dell'header
+		if quality > bestQuality {
+			best, bestQuality = lang, quality
+		}
+	}
+
+	return best
+}
+
+// T restituisce il messaggio con la chiave indicata nella lingua richiesta, formattato con gli argomenti.
+// Se il messaggio manca nella lingua richiesta usa quella predefinita; gli errori tra gli argomenti
+// vengono tradotti con Message.
+func T(lang, key string, args ...any) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		if format, ok = catalogs[DefaultLanguage][key]; !ok {
+			log.Printf("[I18n] Messaggio mancante: %s", key)
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+
+	translated := make([]any, len(args))
+	for i, arg := range args {
+		if err, isErr := arg.(error); isErr {
+			arg = Message(lang, err)
+		}
+		translated[i] = arg
+	}
+	return fmt.Sprintf(format, translated...)
+}
+
+// Error è un errore il cui messaggio proviene dal catalogo e può essere mostrato in qualsiasi lingua
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// Errorf crea un errore traducibile con la chiave del catalogo e i relativi argomenti
+func Errorf(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+// Error restituisce il messaggio nella lingua predefinita
+func (e *Error) Error() string {
+	return T(DefaultLanguage, e.Key, e.Args...)
+}
+
+// Unwrap restituisce gli errori tra gli argomenti, così che errors.Is ed errors.As li trovino
+func (e *Error) Unwrap() []error {
+	var wrapped []error
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			wrapped = append(wrapped, err)
+		}
+	}
+	return wrapped
+}
+
+// Message restituisce il messaggio di un errore nella lingua richiesta; gli errori che non
+// provengono dal catalogo restituiscono il loro messaggio originale
+func Message(lang string, err error) string {
+	if translatable, ok := err.(*Error); ok {
+		return T(lang, translatable.Key, translatable.Args...)
+	}
+	return err.Error()
+}
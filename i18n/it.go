@@ -0,0 +1,352 @@
+package i18n
+
+// italian contiene i messaggi in italiano
+var italian = map[string]string{
+	// Messaggi generali del bot
+	"bot.send_command":      "Invia un comando, ad esempio /help o /price bitcoin",
+	"bot.cancelled":         "Operazione annullata.",
+	"bot.nothing_to_cancel": "Nessuna operazione da annullare.",
+	"bot.unknown_command":   "Comando non riconosciuto. Usa /help per vedere i comandi disponibili.",
+	"help.text": `
+Comandi disponibili:
+/price <crypto_id> [valuta] - Ottiene il prezzo attuale (es: /price bitcoin oppure /price bitcoin eur)
+/search <testo> - Cerca una criptovaluta per nome, simbolo o ID, anche con errori di battitura (es: /search etherium)
+/range <crypto_id> [valuta] - Mostra minimo, massimo e variazione delle ultime 24 ore (solo per le criptovalute monitorate)
+/chart <crypto_id> [24h|7d|30d] [valuta] [--line] - Invia il grafico dei prezzi con le soglie dei tuoi alert (es: /chart bitcoin 7d)
+/create_alert <crypto_id> [>|<|<>] <threshold_price> [valuta] - Crea un nuovo alert (es: /create_alert bitcoin 30000 eur oppure /create_alert ethereum < 2000)
+   Di default l'alert scatta quando il prezzo attraversa la soglia; aggiungi --level per farlo scattare appena il prezzo è oltre la soglia
+   Con --for 7d l'alert scade dopo 7 giorni, con --after 2h viene controllato solo a partire da 2 ore dopo la creazione
+   Opzioni per alert ricorrenti (anche per /percent_alert): --repeat per riarmarlo dopo lo scatto, --cooldown 1h attesa minima tra due scatti, --band 2 distanza percentuale dalla soglia per il riarmo, --max 5 numero massimo di scatti
+/percent_alert <crypto_id> <[+|-]percentuale> [valuta] [--24h] - Alert su variazione percentuale (es: /percent_alert solana 8 per ±8% da ora, /percent_alert bitcoin -5 --24h rispetto a 24 ore prima)
+/update_alert <id> [>|<|<>] <threshold_price> - Aggiorna un alert esistente (es: /update_alert 1 32000; per gli alert percentuali indica la nuova percentuale)
+/alerts - Mostra tutti gli alert
+/active_alerts - Mostra solo gli alert attivi (non triggerati)
+/alert <id> - Mostra i dettagli di un alert specifico
+/delete_alert <id> - Elimina un alert specifico
+/history <id> - Mostra la cronologia di un alert (scatti, reset, modifiche e scadenza)
+/buy <crypto_id> <quantità> [prezzo] [valuta] - Registra un acquisto nel portafoglio (senza prezzo usa quello di mercato)
+/sell <crypto_id> <quantità> [prezzo] [valuta] - Registra una vendita nel portafoglio
+/portfolio - Mostra valore, allocazione e P&L delle tue posizioni
+/portfolio_alert [>|<|<>] <valore> [valuta] - Alert sul valore totale del portafoglio (es: /portfolio_alert < 10000 eur)
+/pnl_alert <crypto_id> <[+|-]percentuale> - Alert sul P&L non realizzato di una posizione (es: /pnl_alert bitcoin 30 per take profit, /pnl_alert bitcoin -20 per stop loss)
+/watch <crypto_id> [crypto_id...] - Aggiunge criptovalute alla tua watchlist (es: /watch bitcoin ethereum solana)
+/unwatch <crypto_id> [crypto_id...] - Rimuove criptovalute dalla watchlist
+/watchlist [valuta] - Mostra prezzi e variazioni nelle 24 ore di tutta la watchlist in un unico messaggio
+/digest daily|weekly [giorno] <HH:MM> [valuta] - Ricevi un riepilogo periodico di watchlist e alert (es: /digest daily 08:00, /digest weekly lun 09:30, /digest off per annullare)
+/new_alert [crypto_id] - Crea un alert passo dopo passo: criptovaluta, direzione, soglia e scadenza
+/cancel - Annulla l'operazione in corso (es. /new_alert o la modifica di una soglia avviata con il pulsante ✏️)
+/language [it|en] - Mostra o cambia la lingua dei messaggi del bot (es: /language en)
+/timezone [fuso_orario] - Mostra o cambia il fuso orario delle date mostrate dal bot (es: /timezone Europe/London)
+/help - Mostra questo messaggio
+
+Gli elenchi di alert e le notifiche includono pulsanti per eliminare, riattivare, sospendere per un'ora o modificare la soglia di un alert.
+`,
+	"common.error":             "Errore: %v",
+	"common.price_unavailable": "Errore: Impossibile ottenere il prezzo per '%s'. Verifica che l'ID sia corretto.",
+	"common.invalid_id":        "ID non valido. Usa un numero intero positivo.",
+	"common.invalid_price":     "Prezzo non valido. Usa un numero decimale.",
+	"common.updated_at":        "Aggiornato il: %s",
+
+	// Lingua
+	"language.current":    "🌐 Lingua attuale: %s\n\nLingue disponibili:\n%s\n\nUsa /language <codice> per cambiarla, es. /language en",
+	"language.invalid":    "Lingua non supportata: %s (lingue disponibili: %s)",
+	"language.save_error": "Errore nel salvataggio della lingua: %v",
+	"language.updated":    "🌐 Lingua impostata: %s",
+
+	// Fuso orario
+	"timezone.current":            "🕐 Fuso orario attuale: %s (ora locale %s)\n\nUsa /timezone <fuso_orario> per cambiarlo, es. /timezone Europe/London",
+	"timezone.usage":              "Formato: /timezone <fuso_orario>, con il nome IANA del fuso orario (es. Europe/Rome, Europe/London, America/New_York o UTC)",
+	"timezone.save_error":         "Errore nel salvataggio del fuso orario: %v",
+	"timezone.updated":            "🕐 Fuso orario impostato: %s (ora locale %s)",
+	"timezone.digest_rescheduled": "Il digest seguirà il nuovo fuso orario, prossimo invio: %s",
+
+	// Prezzi, storico e grafici
+	"price.usage":            "Specifica l'ID della criptovaluta. Esempio: /price bitcoin",
+	"price.freshness":        "🕒 Aggiornato alle %s, %v fa",
+	"range.usage":            "Specifica l'ID della criptovaluta. Esempio: /range bitcoin",
+	"range.error":            "Errore nel recupero dello storico: %v",
+	"range.no_data":          "Nessun dato storico per %s in %s nelle ultime 24 ore. Lo storico viene registrato solo per le criptovalute con alert attivi.",
+	"range.summary":          "📊 %s nelle ultime 24 ore (%s)\n\nApertura: %s\nMassimo: %s\nMinimo: %s\nUltimo: %s\nVariazione: %+.2f%%\nOscillazione: %.2f%%\n\nDal %s al %s, %d campioni",
+	"chart.usage":            "Formato: /chart <crypto_id> [24h|7d|30d] [valuta] [--line]",
+	"chart.invalid_argument": "Errore: %v\nI periodi disponibili sono 24h, 7d e 30d.",
+	"chart.history_error":    "Errore: impossibile ottenere lo storico di '%s': %v",
+	"chart.render_error":     "Errore nella creazione del grafico: %v",
+	"chart.caption":          "📈 %s, %s: %+.2f%%\nMin %s | Max %s | Ultimo %s",
+	"chart.threshold_lines":  "Linee tratteggiate: soglie dei tuoi %d alert attivi",
+	"chart.partial_history":  "ℹ️ Storico disponibile solo dal %s",
+	"chart.period_24h":       "ultime 24 ore",
+	"chart.period_7d":        "ultimi 7 giorni",
+	"chart.period_30d":       "ultimi 30 giorni",
+
+	// Ricerca
+	"search.usage":        "Specifica il nome, il simbolo o l'ID da cercare. Esempio: /search bitcoin",
+	"search.unavailable":  "L'elenco delle criptovalute non è ancora disponibile, riprova più tardi.",
+	"search.no_results":   "Nessuna criptovaluta trovata per '%s'.",
+	"search.header":       "🔎 Risultati per '%s':\n\n",
+	"search.rank":         " - #%d per capitalizzazione",
+	"search.hint":         "\nUsa l'ID nei comandi, es. /price %s",
+	"search.did_you_mean": "Forse intendevi: %s?",
+
+	// Alert
+	"alert.create_error":                 "Errore nella creazione dell'alert: %v",
+	"alert.created":                      "✅ Alert creato! ID: %d\nCrypto: %s\nSoglia: %s\nPrezzo attuale: %s\nCreato il: %s",
+	"alert.percent_created":              "✅ Alert percentuale creato! ID: %d\nCrypto: %s\nCondizione: %s\nPrezzo attuale: %s (%+.2f%%)\nCreato il: %s",
+	"alert.portfolio_created":            "✅ Alert sul portafoglio creato! ID: %d\n%s\nSoglia: %s\n%s\nCreato il: %s",
+	"alert.update_usage":                 "Formato: /update_alert <id> [>|<|<>] <threshold_price>",
+	"alert.not_found_edit":               "Alert non trovato o non hai i permessi per modificarlo.",
+	"alert.not_found_view":               "Alert non trovato o non hai i permessi per visualizzarlo.",
+	"alert.not_found_delete":             "Alert non trovato o non hai i permessi per eliminarlo.",
+	"alert.update_price_warning":         "Avviso: Impossibile ottenere il prezzo aggiornato: %v",
+	"alert.update_error":                 "Errore nell'aggiornamento dell'alert: %v",
+	"alert.status_reset":                 "⚠️ Lo stato è stato reimpostato da triggerato a attivo!",
+	"alert.updated":                      "✅ Alert aggiornato! ID: %d\n%s\nNuova soglia: %s\n%s\nStato: %s%s",
+	"alert.list_error":                   "Errore nel recupero degli alert: %v",
+	"alert.list_empty":                   "Non hai alert salvati.",
+	"alert.list_header":                  "📊 I tuoi alert:\n\n",
+	"alert.list_item":                    "ID: %d | %s\n%s\nSoglia: %s\n%s\n%s%s\n",
+	"alert.active_list_error":            "Errore nel recupero degli alert attivi: %v",
+	"alert.active_list_empty":            "Non hai alert attivi.",
+	"alert.active_list_header":           "⚡ I tuoi alert attivi:\n\n",
+	"alert.active_list_item":             "ID: %d\n%s\nSoglia: %s\n%s\n%s\n\n",
+	"alert.triggered_at":                 "Triggerato il: %s",
+	"alert.created_at":                   "Creato il: %s",
+	"alert.id_required":                  "Specifica l'ID dell'alert. Esempio: %s 1",
+	"alert.detail":                       "🔔 Alert #%d\n\n%s\nSoglia: %s\n%s\nStato: %s\n%s",
+	"alert.delete_error":                 "Errore nella cancellazione: %v",
+	"alert.deleted":                      "🗑️ Alert #%d eliminato con successo.",
+	"alert.warning_already_met_level":    "La condizione è già soddisfatta dal prezzo attuale: l'alert scatterà al prossimo controllo.",
+	"alert.warning_already_met_crossing": "La condizione è già soddisfatta dal prezzo attuale: l'alert scatterà solo quando il prezzo attraverserà di nuovo la soglia.",
+	"status.expired":                     "⌛ Scaduto",
+	"status.triggered":                   "✅ Triggerato",
+	"status.scheduled":                   "🕒 Programmato",
+	"status.waiting":                     "⏳ In attesa",
+
+	// Cronologia degli alert
+	"history.error":              "Errore nel recupero della cronologia: %v",
+	"history.empty":              "L'alert #%d non ha ancora eventi registrati.",
+	"history.header":             "📜 Cronologia alert #%d (%s, %s)\n\n",
+	"history.item":               "%s - %s a %s\n",
+	"event.trigger":              "🚨 Scattato",
+	"event.reset":                "🔄 Riarmato",
+	"event.update":               "✏️ Modificato",
+	"event.expiry":               "⌛ Scaduto",
+	"event.reason.manual":        "reset manuale",
+	"event.reason.automatic":     "riarmo automatico",
+	"event.change":               "%s %s → %s",
+	"event.value_none":           "nessuno",
+	"event.value_yes":            "sì",
+	"event.value_no":             "no",
+	"event.field.threshold":      "soglia",
+	"event.field.percent_change": "variazione",
+	"event.field.direction":      "direzione",
+	"event.field.trigger_mode":   "modalità",
+	"event.field.recurring":      "ricorrente",
+	"event.field.cooldown":       "cooldown (minuti)",
+	"event.field.hysteresis":     "isteresi (%)",
+	"event.field.max_fires":      "scatti massimi",
+	"event.field.active_from":    "attivo dal",
+	"event.field.expires_at":     "scadenza",
+
+	// Notifiche
+	"notification.triggered": "🚨 ALERT TRIGGERATO! 🚨\n\nID: %d\n%s\nSoglia: %s\n%s\nData: %s",
+	"notification.change":    "Variazione: %+.2f%% (riferimento %s)",
+	"notification.expired":   "⌛ Alert scaduto\n\nID: %d\n%s\nSoglia: %s\n%s\n\nL'alert non è mai scattato e non verrà più controllato.",
+
+	// Argomenti dei comandi
+	"args.option_without_value":  "Opzione %s senza valore",
+	"args.invalid_band":          "Banda di isteresi non valida. Usa una percentuale, es. --band 2",
+	"args.invalid_max":           "Numero massimo di scatti non valido, es. --max 5",
+	"args.unknown_option":        "Opzione non riconosciuta: %s",
+	"args.invalid_duration":      "Durata non valida: %s (usa ad esempio 30m, 6h, 7d o 2w)",
+	"args.invalid_cooldown":      "Cooldown non valido: %s (usa almeno 1 minuto, es. 30m o 1h)",
+	"args.rolling_percent_only":  "L'opzione --24h vale solo per /percent_alert",
+	"args.create_alert_usage":    "Formato: /create_alert <crypto_id> [>|<|<>] <threshold_price> [valuta] %s",
+	"args.threshold_required":    "Specifica il prezzo soglia. Esempio: /create_alert bitcoin < 60000",
+	"args.invalid_threshold":     "Soglia non valida. Usa un numero positivo.",
+	"args.percent_alert_usage":   "Formato: /percent_alert <crypto_id> <[+|-]percentuale> [valuta] [--24h] %s",
+	"args.invalid_percent":       "Percentuale non valida. Usa un numero positivo, es. 8, +8 o -8.",
+	"args.portfolio_alert_usage": "Formato: /portfolio_alert [>|<|<>] <valore> [valuta] %s",
+	"args.invalid_value":         "Valore non valido. Usa un numero positivo.",
+	"args.pnl_alert_usage":       "Formato: /pnl_alert <crypto_id> <[+|-]percentuale> %s",
+	"args.invalid_pnl_percent":   "Percentuale non valida. Usa un numero diverso da zero, es. 30 oppure -20.",
+
+	// Formattazione degli alert
+	"format.reference_from":    "da %s",
+	"format.reference_24h":     "nelle 24h",
+	"format.subject_portfolio": "portafoglio %s",
+	"format.subject_position":  "posizione %s",
+	"format.label_portfolio":   "Portafoglio: %s",
+	"format.label_position":    "Posizione: %s",
+	"format.label_crypto":      "Crypto: %s",
+	"format.current_portfolio": "Valore portafoglio: %s",
+	"format.current_position":  "P&L posizione: %s",
+	"format.current_price":     "Prezzo attuale: %s",
+	"format.repeat_fires":      "%s scatti",
+	"format.repeat_cooldown":   "cooldown %d min",
+	"format.repeat_band":       "banda %.2f%%",
+	"format.repeat":            "🔁 Ricorrente: %s",
+	"format.active_from":       "Attivo dal: %s",
+	"format.expires_at":        "Scade il: %s",
+
+	// Pulsanti e azioni rapide
+	"button.delete":                  "🗑 Elimina",
+	"button.reset":                   "🔄 Reset",
+	"button.snooze":                  "💤 1h",
+	"button.edit":                    "✏️ Soglia",
+	"callback.unknown_action":        "Azione non riconosciuta.",
+	"callback.deleted":               "🗑️ Alert #%d eliminato",
+	"callback.already_active":        "L'alert #%d è già attivo.",
+	"callback.reset":                 "🔄 Alert #%d riattivato",
+	"callback.expires_before_snooze": "L'alert #%d scade prima della fine della sospensione.",
+	"callback.snoozed":               "💤 Alert #%d sospeso fino alle %s",
+	"callback.edit_prompt":           "✏️ Invia la nuova soglia per l'alert #%d (%s, %s), es. 32000 oppure < 30000.\nUsa /cancel per annullare.",
+
+	// Creazione guidata degli alert
+	"conversation.start":             "🆕 Creazione guidata di un alert (usa /cancel per annullare).\n\nPer quale criptovaluta? Scegli un suggerimento o scrivi l'ID CoinGecko (es. bitcoin).",
+	"conversation.coin_not_found":    "Non trovo la criptovaluta '%s'. Controlla l'ID CoinGecko e riprova, oppure usa /cancel.",
+	"conversation.ask_direction":     "💰 %s: %s\n\nQuando vuoi essere avvisato?",
+	"conversation.button_above":      "⬆️ Sopra la soglia",
+	"conversation.button_below":      "⬇️ Sotto la soglia",
+	"conversation.button_cross":      "↕️ Attraversamento",
+	"conversation.word_above":        "sopra",
+	"conversation.word_below":        "sotto",
+	"conversation.word_cross":        "attraversamento",
+	"conversation.invalid_direction": "Scelta non valida. Usa uno dei pulsanti oppure >, < o <>.",
+	"conversation.ask_threshold":     "Invia il prezzo soglia in %s (prezzo attuale %s).",
+	"conversation.invalid_threshold": "Prezzo non valido. Invia un numero positivo, es. 65000.",
+	"conversation.ask_expiry":        "Per quanto tempo deve restare attivo l'alert?",
+	"conversation.button_no_expiry":  "Nessuna scadenza",
+	"conversation.timeout":           "⌛ Operazione annullata per inattività. Ricomincia quando vuoi, ad esempio con /new_alert.",
+
+	// Portafoglio
+	"trade.usage":                            "Formato: /%s <crypto_id> <quantità> [prezzo] [valuta]\nEsempio: /%s bitcoin 0.25 60000",
+	"trade.invalid_quantity":                 "Quantità non valida. Usa un numero positivo.",
+	"trade.invalid_price":                    "Prezzo non valido. Usa un numero positivo.",
+	"trade.error":                            "Errore nella registrazione della transazione: %v",
+	"trade.buy_recorded":                     "🟢 Acquisto registrato",
+	"trade.sell_recorded":                    "🔴 Vendita registrata",
+	"trade.recorded":                         "%s\n\n%g %s a %s (totale %s)\nPosizione: %g %s, costo medio %s",
+	"trade.realized_pnl":                     "P&L realizzato totale: %s",
+	"portfolio.load_error":                   "Errore nel recupero del portafoglio: %v",
+	"portfolio.empty":                        "Il tuo portafoglio è vuoto. Registra un acquisto con /buy <crypto_id> <quantità> [prezzo].",
+	"portfolio.prices_error":                 "Errore: impossibile ottenere i prezzi per valutare il portafoglio: %v",
+	"portfolio.header":                       "💼 Il tuo portafoglio\n\n",
+	"portfolio.price_unavailable":            "Prezzo non disponibile\n\n",
+	"portfolio.position":                     "Valore: %s (%.1f%%)\nP&L: %s (%+.2f%%)\n\n",
+	"portfolio.total":                        "📊 Totale %s: %s\nP&L non realizzato: %s (%+.2f%%)\nP&L realizzato: %s\n",
+	"portfolio.valuation_error":              "Errore: impossibile valutare il portafoglio: %v",
+	"portfolio.insufficient_quantity":        "quantità insufficiente",
+	"portfolio.insufficient_quantity_detail": "%v: possiedi %g %s",
+	"portfolio.currency_mismatch":            "valuta diversa da quella della posizione",
+	"portfolio.currency_mismatch_detail":     "%v: la posizione su %s è registrata in %s",
+	"portfolio.no_position":                  "nessuna posizione aperta su %s",
+	"portfolio.no_valued_position":           "nessuna posizione valutabile in %s",
+	"portfolio.invalid_trade":                "quantità e prezzo devono essere positivi",
+
+	// Watchlist
+	"watchlist.watch_usage":       "Specifica una o più criptovalute. Esempio: /watch bitcoin ethereum solana",
+	"watchlist.unwatch_usage":     "Specifica una o più criptovalute. Esempio: /unwatch solana",
+	"watchlist.verify_error":      "Errore: impossibile verificare le criptovalute: %v",
+	"watchlist.update_error":      "Errore nell'aggiornamento della watchlist: %v",
+	"watchlist.added":             "👀 Aggiunte alla watchlist: %s\n",
+	"watchlist.already_present":   "Già presenti: %s\n",
+	"watchlist.not_found":         "⚠️ Non trovate: %s. Verifica che gli ID siano corretti.\n",
+	"watchlist.hint":              "\nUsa /watchlist per vedere i prezzi.",
+	"watchlist.none_removed":      "Nessuna delle criptovalute indicate è nella tua watchlist.",
+	"watchlist.removed":           "🗑️ Rimosse %d criptovalute dalla watchlist.",
+	"watchlist.load_error":        "Errore nel recupero della watchlist: %v",
+	"watchlist.empty":             "La tua watchlist è vuota. Aggiungi criptovalute con /watch <crypto_id>, es. /watch bitcoin ethereum",
+	"watchlist.prices_error":      "Errore: impossibile ottenere i prezzi della watchlist: %v",
+	"watchlist.header":            "👀 La tua watchlist (%s)\n\n",
+	"watchlist.price_unavailable": "%s: prezzo non disponibile\n",
+	"watchlist.full":              "la watchlist può contenere al massimo %d criptovalute",
+
+	// Digest
+	"digest.usage":            "Formato:\n/digest daily 08:00 [valuta] - riepilogo ogni giorno\n/digest weekly lun 08:00 [valuta] - riepilogo ogni settimana\n/digest now - invia subito il riepilogo\n/digest off - annulla l'iscrizione",
+	"digest.load_error":       "Errore nel recupero del digest: %v",
+	"digest.not_subscribed":   "Non sei iscritto a nessun digest.",
+	"digest.status":           "📬 Digest %s\nProssimo invio: %s\n\n%s",
+	"digest.delete_error":     "Errore nella cancellazione del digest: %v",
+	"digest.unsubscribed":     "🔕 Iscrizione al digest annullata.",
+	"digest.build_error":      "Errore nella preparazione del digest: %v",
+	"digest.save_error":       "Errore nel salvataggio del digest: %v",
+	"digest.saved":            "📬 Digest impostato: %s\nProssimo invio: %s\n\nIl riepilogo include watchlist, alert attivi e alert scattati dall'invio precedente.",
+	"digest.weekday_required": "Specifica il giorno della settimana, es. /digest weekly lun 08:00",
+	"digest.invalid_weekday":  "Giorno non valido: %q (usa lun, mar, mer, gio, ven, sab o dom)",
+	"digest.time_required":    "Specifica l'orario di invio, es. 08:00",
+	"digest.every_day":        "ogni giorno",
+	"digest.every_weekday":    "ogni %s",
+	"digest.schedule":         "%s alle %02d:%02d, prezzi in %s",
+	"digest.header":           "📬 Il tuo riepilogo del %s\n",
+	"digest.empty":            "\nNiente da segnalare: aggiungi criptovalute con /watch o crea un alert con /create_alert.",
+	"digest.fired_header":     "\n🚨 Alert scattati dal %s: %d\n",
+	"digest.fired_item":       "#%d %s %s - %s a %s\n",
+	"digest.active_header":    "\n⏳ Alert attivi: %d\n",
+	"digest.active_item":      "#%d %s %s (ora %s)\n",
+	"weekday.0":               "domenica",
+	"weekday.1":               "lunedì",
+	"weekday.2":               "martedì",
+	"weekday.3":               "mercoledì",
+	"weekday.4":               "giovedì",
+	"weekday.5":               "venerdì",
+	"weekday.6":               "sabato",
+
+	// Validazione dei valori
+	"validation.invalid_direction":        "direzione non valida: %q (usa above, below o cross)",
+	"validation.invalid_trigger_mode":     "modalità non valida: %q (usa crossing o level)",
+	"validation.invalid_alert_type":       "tipo di alert non valido: %q (usa price, percent, portfolio_value o position_pnl)",
+	"validation.invalid_baseline":         "riferimento non valido: %q (usa fixed o 24h)",
+	"validation.change24h_unavailable":    "variazione nelle 24 ore non disponibile per %s",
+	"validation.invalid_frequency":        "frequenza non valida: %q (usa daily o weekly)",
+	"validation.invalid_clock":            "orario non valido: %q (usa il formato HH:MM, es. 08:00)",
+	"validation.invalid_transaction_type": "tipo di transazione non valido: %q (usa buy o sell)",
+	"validation.unsupported_currency":     "valuta non supportata: %s (valute disponibili: %s)",
+	"validation.invalid_timezone":         "fuso orario non valido: %q",
+	"validation.invalid_chat_status":      "stato della chat non valido: %q (usa active o blocked)",
+	"validation.invalid_interval":         "intervallo non valido: %q (usa ad esempio 15m, 1h o 1d)",
+
+	// Errori delle API REST
+	"api.crypto_id_required":        "crypto_id è obbligatorio",
+	"api.crypto_id_missing":         "ID criptovaluta non fornito",
+	"api.crypto_ids_required":       "crypto_id o crypto_ids è obbligatorio",
+	"api.user_chat_id_required":     "user_chat_id è obbligatorio",
+	"api.threshold_required":        "threshold_price è obbligatorio e deve essere positivo per gli alert di tipo %s",
+	"api.percent_change_required":   "percent_change è obbligatorio e deve essere positivo per gli alert di tipo percent",
+	"api.pnl_percent_required":      "percent_change è obbligatorio per gli alert di tipo position_pnl (es. 30 o -20)",
+	"api.negative_recurrence":       "cooldown_minutes, hysteresis_percent e max_fires non possono essere negativi",
+	"api.expires_in_past":           "expires_at deve essere una data futura",
+	"api.expires_before_active":     "expires_at deve essere successiva ad active_from",
+	"api.portfolio_valuation_error": "Impossibile valutare il portafoglio: %v",
+	"api.price_unavailable":         "Impossibile ottenere il prezzo per la criptovaluta fornita. Verifica che l'ID sia corretto.",
+	"api.alerts_error":              "Errore nel recupero degli alert",
+	"api.active_alerts_error":       "Errore nel recupero degli alert attivi",
+	"api.no_alerts":                 "Nessun alert trovato",
+	"api.no_active_alerts":          "Nessun alert attivo trovato",
+	"api.alert_not_found":           "Alert non trovato",
+	"api.history_error":             "Errore nel recupero della cronologia dell'alert",
+	"api.delete_error":              "Errore nella cancellazione",
+	"api.alert_deleted":             "Alert eliminato",
+	"api.query_required":            "Parametro q obbligatorio",
+	"api.invalid_limit":             "limit deve essere un numero tra 1 e %d",
+	"api.invalid_param":             "Parametro %s non valido: %v",
+	"api.invalid_time_format":       "usa il formato RFC 3339 (es. 2026-01-02T15:04:05Z) o un timestamp Unix",
+	"api.from_after_to":             "from deve precedere to",
+	"api.range_too_long":            "Il periodo richiesto non può superare un anno",
+	"api.price_history_error":       "Errore nel recupero dello storico prezzi",
+	"api.portfolio_error":           "Errore nel recupero del portafoglio",
+	"api.portfolio_prices_error":    "Impossibile ottenere i prezzi per valutare il portafoglio",
+	"api.transactions_error":        "Errore nel recupero delle transazioni",
+	"api.invalid_trade":             "quantity deve essere positiva e price non può essere negativo",
+	"api.transaction_error":         "Errore nella registrazione della transazione",
+	"api.watchlist_error":           "Errore nel recupero della watchlist",
+	"api.watchlist_prices_error":    "Impossibile ottenere i prezzi della watchlist",
+	"api.coins_verify_error":        "Impossibile verificare le criptovalute fornite",
+	"api.coins_not_found":           "Criptovalute non trovate: %s. Verifica che gli ID siano corretti.",
+	"api.watchlist_update_error":    "Errore nell'aggiornamento della watchlist",
+	"api.not_in_watchlist":          "Criptovaluta non presente nella watchlist",
+	"api.watchlist_removed":         "Criptovaluta rimossa dalla watchlist",
+	"api.unauthorized":              "Token di amministrazione mancante o non valido",
+	"api.invalid_offset":            "offset deve essere un numero non negativo",
+	"api.chats_error":               "Errore nel recupero delle chat",
+	"api.chat_not_found":            "Chat non trovata",
+}
@@ -5,11 +5,17 @@ import (
 	"crypto-tracker/models"
 	"crypto-tracker/routes"
 	"crypto-tracker/services"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/history"
+	"crypto-tracker/services/pricing"
 	"crypto-tracker/services/telegram"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+	_ "time/tzdata" // Database dei fusi orari incluso nel binario, per /timezone anche senza tzdata nel sistema
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -29,15 +35,34 @@ func main() {
 	}
 
 	// Migrazione automatica degli schemi
-	err := db.AutoMigrate(&models.Alert{})
+	err := db.AutoMigrate(&models.Alert{}, &models.AlertEvent{}, &models.PriceSample{}, &models.Holding{}, &models.Transaction{}, &models.WatchlistItem{}, &models.DigestSchedule{}, &models.Coin{}, &models.Chat{})
 	if err != nil {
 		log.Fatalf("Errore durante la migrazione: %v", err)
 	}
 
-	alertMonitor := services.NewAlertMonitor(db, 5*time.Minute)
+	// Sorgente dei prezzi condivisa da monitor, bot e API REST, con cache in memoria
+	priceProvider := pricing.NewCache(pricing.NewProviderFromEnv(), durationFromEnv("PRICE_CACHE_TTL", pricing.DefaultCacheTTL))
+
+	// Storico dei prezzi, alimentato dal monitor a ogni controllo
+	priceHistory := history.NewStore(db,
+		durationFromEnv("PRICE_HISTORY_RETENTION", history.DefaultRetention),
+		durationFromEnv("PRICE_HISTORY_RAW_RETENTION", history.DefaultRawRetention))
+
+	// Registro delle criptovalute, per risolvere simboli e nomi negli ID di CoinGecko
+	coinRegistry := coins.NewRegistry(db, priceProvider, durationFromEnv("COIN_REGISTRY_REFRESH", coins.DefaultRefreshInterval))
+	coinRegistry.Start()
+	defer coinRegistry.Stop()
+
+	alertMonitor := services.NewAlertMonitor(db, priceProvider, 5*time.Minute)
+	alertMonitor.SetHistory(priceHistory)
 	alertMonitor.Start()
 	defer alertMonitor.Stop()
 
+	// Scheduler dei digest periodici, con le pianificazioni salvate nel database
+	digestScheduler := services.NewDigestScheduler(db, priceProvider, time.Minute)
+	digestScheduler.Start()
+	defer digestScheduler.Stop()
+
 	// Inizializza il bot Telegram
 	telegramToken := os.Getenv("TELEGRAM_BOT_TOKEN")
 	if telegramToken == "" {
@@ -45,7 +70,7 @@ func main() {
 	} else {
 		log.Printf("Avvio del bot Telegram con token: %s***", telegramToken[:10])
 
-		bot, err := telegram.NewTelegramBot(telegramToken, db)
+		bot, err := telegram.NewTelegramBot(telegramToken, db, priceProvider, priceHistory, coinRegistry)
 		if err != nil {
 			log.Printf("⚠️ ERRORE nell'inizializzazione del bot Telegram: %v", err)
 		} else {
@@ -60,6 +85,9 @@ func main() {
 			// Collega il canale di notifica del bot al monitor degli alert
 			alertMonitor.SetTelegramNotificationChannel(bot.GetNotificationChannel())
 			log.Println("Notifiche Telegram configurate per gli alert")
+
+			// Collega il canale dei digest del bot allo scheduler
+			digestScheduler.SetTelegramDigestChannel(bot.GetDigestChannel())
 		}
 	}
 
@@ -77,8 +105,18 @@ func main() {
 	}))
 
 	// Imposta le routes
-	routes.SetupAlertRoutes(router, db)
-	routes.SetupCryptoRoutes(router, db)
+	routes.SetupAlertRoutes(router, db, priceProvider, coinRegistry)
+	routes.SetupCryptoRoutes(router, db, priceProvider, priceHistory, coinRegistry)
+	routes.SetupPortfolioRoutes(router, db, priceProvider, coinRegistry)
+	routes.SetupWatchlistRoutes(router, db, priceProvider, coinRegistry)
+	routes.SetupCoinRoutes(router, coinRegistry)
+
+	// Le routes di amministrazione sono attive solo se è impostato un token
+	if adminToken := os.Getenv("ADMIN_API_TOKEN"); adminToken != "" {
+		routes.SetupAdminRoutes(router, db, adminToken)
+	} else {
+		log.Println("ADMIN_API_TOKEN non impostato, le API di amministrazione non saranno disponibili")
+	}
 
 	// Avvia il server
 	port := ":8080"
@@ -87,3 +125,27 @@ func main() {
 		log.Fatalf("Errore nell'avvio del server: %v", err)
 	}
 }
+
+// durationFromEnv legge una durata (es. "90s", "2m", "30d") da una variabile d'ambiente,
+// restituendo def se la variabile non è impostata o non è valida
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	// I giorni non sono supportati da time.ParseDuration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Duration(n) * 24 * time.Hour
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Valore non valido per %s (%q), uso il default %v", key, value, def)
+		return def
+	}
+
+	return d
+}
@@ -1,18 +1,300 @@
 package models
 
 import (
+	"crypto-tracker/i18n"
+	"math"
+	"strings"
 	"time"
 )
 
+// Direzioni possibili per la condizione di un alert
+const (
+	DirectionAbove = "above" // il prezzo sale fino alla soglia o oltre
+	DirectionBelow = "below" // il prezzo scende fino alla soglia o sotto
+	DirectionCross = "cross" // il prezzo attraversa la soglia in una qualsiasi direzione
+)
+
+// Tipi di alert
+const (
+	AlertTypePrice          = "price"           // soglia di prezzo assoluta
+	AlertTypePercent        = "percent"         // variazione percentuale rispetto a un prezzo di riferimento
+	AlertTypePortfolioValue = "portfolio_value" // soglia sul valore totale del portafoglio nella valuta dell'alert
+	AlertTypePositionPnL    = "position_pnl"    // soglia sul P&L percentuale non realizzato di una posizione
+)
+
+// Prezzi di riferimento per gli alert percentuali
+const (
+	BaselineFixed   = "fixed" // prezzo al momento della creazione
+	BaselineRolling = "24h"   // prezzo di 24 ore prima, aggiornato a ogni controllo
+)
+
+// Modalità di valutazione della condizione di un alert
+const (
+	TriggerModeCrossing = "crossing" // scatta solo quando il prezzo attraversa la soglia tra due osservazioni
+	TriggerModeLevel    = "level"    // scatta appena il prezzo si trova oltre la soglia
+)
+
 // Alert rappresenta una soglia di prezzo per una criptovaluta
 type Alert struct {
-	ID             uint       `gorm:"primaryKey"`
-	UserChatID     int64      `gorm:"index;not null;default:0"`                                 // ID della chat Telegram dell'utente che ha creato l'alert
-	CryptoID       string     `gorm:"column:cryptocurrency_id;type:varchar(50);not null;index"` // ID della criptovaluta per CoinGecko (es. "bitcoin", "ethereum")
-	ThresholdPrice float64    `gorm:"type:decimal(20,8);not null"`                              // Prezzo soglia per l'alert
-	CurrentPrice   float64    `gorm:"type:decimal(20,8);"`                                      // Prezzo corrente (ultimo noto)
-	Triggered      bool       `gorm:"default:false;not null"`                                   // Se l'alert è stato attivato
-	NotifiedAt     *time.Time `gorm:"type:timestamp"`                                           // Quando è stata inviata la notifica
-	CreatedAt      time.Time  `gorm:"type:timestamp;not null"`
-	UpdatedAt      time.Time  `gorm:"type:timestamp;not null"`
+	ID                uint       `gorm:"primaryKey"`
+	UserChatID        int64      `gorm:"index;not null;default:0"`                                 // ID della chat Telegram dell'utente che ha creato l'alert
+	CryptoID          string     `gorm:"column:cryptocurrency_id;type:varchar(50);not null;index"` // ID della criptovaluta per CoinGecko (es. "bitcoin", "ethereum")
+	Type              string     `gorm:"type:varchar(20);not null;default:'price'"`                // Tipo di alert: price, percent, portfolio_value o position_pnl
+	Currency          string     `gorm:"type:varchar(10);not null;default:'usd'"`                  // Valuta di quotazione di soglia e prezzo (es. "usd", "eur", "btc")
+	Direction         string     `gorm:"type:varchar(10);not null;default:'above'"`                // Direzione della condizione: above, below o cross
+	TriggerMode       string     `gorm:"type:varchar(10);not null;default:'crossing'"`             // Modalità di valutazione: crossing o level
+	ThresholdPrice    float64    `gorm:"type:decimal(20,8);not null"`                              // Soglia dell'alert: prezzo, valore del portafoglio o P&L percentuale a seconda del tipo
+	PercentChange     float64    `gorm:"type:decimal(10,4);not null;default:0"`                    // Variazione percentuale attesa (solo alert di tipo percent)
+	ReferencePrice    float64    `gorm:"type:decimal(20,8);not null;default:0"`                    // Prezzo di riferimento per la variazione percentuale
+	Baseline          string     `gorm:"type:varchar(10);not null;default:'fixed'"`                // Riferimento della variazione: fixed o 24h
+	CurrentPrice      float64    `gorm:"type:decimal(20,8);"`                                      // Ultimo valore osservato (prezzo, valore del portafoglio o P&L percentuale), usato per rilevare gli attraversamenti
+	ObservedAt        *time.Time `gorm:"type:timestamp"`                                           // Quando è stato osservato CurrentPrice (nil = mai, CurrentPrice non è un'osservazione)
+	Triggered         bool       `gorm:"default:false;not null"`                                   // Se l'alert è stato attivato
+	NotifiedAt        *time.Time `gorm:"type:timestamp"`                                           // Quando è stata inviata la notifica (l'ultima, per gli alert ricorrenti)
+	Recurring         bool       `gorm:"default:false;not null"`                                   // Se l'alert si riarma automaticamente dopo essere scattato
+	CooldownMinutes   int        `gorm:"not null;default:0"`                                       // Minuti di attesa dopo uno scatto prima del riarmo
+	HysteresisPercent float64    `gorm:"type:decimal(10,4);not null;default:0"`                    // Distanza percentuale dalla soglia da superare prima del riarmo
+	MaxFires          int        `gorm:"not null;default:0"`                                       // Numero massimo di scatti (0 = illimitato)
+	FireCount         int        `gorm:"not null;default:0"`                                       // Numero di volte in cui l'alert è scattato
+	ActiveFrom        *time.Time `gorm:"type:timestamp"`                                           // Da quando l'alert viene valutato (nil = subito)
+	ExpiresAt         *time.Time `gorm:"type:timestamp;index"`                                     // Quando l'alert scade (nil = mai)
+	Expired           bool       `gorm:"default:false;not null"`                                   // Se l'alert è scaduto e non viene più valutato
+	CreatedAt         time.Time  `gorm:"type:timestamp;not null"`
+	UpdatedAt         time.Time  `gorm:"type:timestamp;not null"`
+}
+
+// ParseDirection converte una direzione testuale o simbolica (">", "<", "<>") nel valore salvato.
+// Una stringa vuota corrisponde a DirectionAbove, il comportamento storico degli alert.
+func ParseDirection(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", DirectionAbove, ">", ">=":
+		return DirectionAbove, nil
+	case DirectionBelow, "<", "<=":
+		return DirectionBelow, nil
+	case DirectionCross, "<>", "><":
+		return DirectionCross, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_direction", value)
+	}
+}
+
+// ParseTriggerMode converte la modalità testuale nel valore salvato; una stringa vuota corrisponde a crossing
+func ParseTriggerMode(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", TriggerModeCrossing:
+		return TriggerModeCrossing, nil
+	case TriggerModeLevel:
+		return TriggerModeLevel, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_trigger_mode", value)
+	}
+}
+
+// ParseAlertType converte il tipo testuale nel valore salvato; una stringa vuota corrisponde a price
+func ParseAlertType(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", AlertTypePrice:
+		return AlertTypePrice, nil
+	case AlertTypePercent:
+		return AlertTypePercent, nil
+	case AlertTypePortfolioValue:
+		return AlertTypePortfolioValue, nil
+	case AlertTypePositionPnL:
+		return AlertTypePositionPnL, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_alert_type", value)
+	}
+}
+
+// ParseBaseline converte il riferimento testuale nel valore salvato; una stringa vuota corrisponde a fixed
+func ParseBaseline(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", BaselineFixed:
+		return BaselineFixed, nil
+	case BaselineRolling:
+		return BaselineRolling, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_baseline", value)
+	}
+}
+
+// IsPortfolio indica se l'alert riguarda il portafoglio (valore totale o P&L di una posizione)
+// invece del prezzo di una criptovaluta
+func (a *Alert) IsPortfolio() bool {
+	return a.Type == AlertTypePortfolioValue || a.Type == AlertTypePositionPnL
+}
+
+// bounds restituisce le soglie di prezzo effettive dell'alert: quella superiore (upper)
+// e quella inferiore (lower), ciascuna presente solo se rilevante per la direzione
+func (a *Alert) bounds() (upper, lower float64, hasUpper, hasLower bool) {
+	upper, lower = a.ThresholdPrice, a.ThresholdPrice
+	if a.Type == AlertTypePercent {
+		upper = a.ReferencePrice * (1 + a.PercentChange/100)
+		lower = a.ReferencePrice * (1 - a.PercentChange/100)
+	}
+
+	switch a.Direction {
+	case DirectionBelow:
+		return upper, lower, false, true
+	case DirectionCross:
+		return upper, lower, true, true
+	default:
+		return upper, lower, true, false
+	}
+}
+
+// Thresholds restituisce le soglie di prezzo effettive dell'alert (es. per disegnarle su un grafico)
+func (a *Alert) Thresholds() []float64 {
+	upper, lower, hasUpper, hasLower := a.bounds()
+
+	var thresholds []float64
+	if hasUpper {
+		thresholds = append(thresholds, upper)
+	}
+	if hasLower && (!hasUpper || lower != upper) {
+		thresholds = append(thresholds, lower)
+	}
+	return thresholds
+}
+
+// ConditionMet indica se il prezzo soddisfa la condizione dell'alert in base al solo livello.
+// Per gli alert di prezzo "cross" la condizione dipende dal prezzo precedente e il solo livello non basta.
+func (a *Alert) ConditionMet(price float64) bool {
+	upper, lower, hasUpper, hasLower := a.bounds()
+	if hasUpper && hasLower && upper == lower {
+		return false
+	}
+
+	return (hasUpper && price >= upper) || (hasLower && price <= lower)
+}
+
+// IsTriggeredBy indica se l'alert deve scattare passando dal prezzo previous (nil se non c'è
+// un'osservazione precedente) al prezzo price. In modalità crossing (e sempre per gli alert di prezzo
+// con direzione cross) serve un attraversamento effettivo della soglia tra le due osservazioni;
+// in modalità level basta il livello.
+func (a *Alert) IsTriggeredBy(previous *float64, price float64) bool {
+	upper, lower, hasUpper, hasLower := a.bounds()
+	singleCross := hasUpper && hasLower && upper == lower
+
+	if a.TriggerMode == TriggerModeLevel && !singleCross {
+		return a.ConditionMet(price)
+	}
+
+	// Senza un'osservazione precedente non è possibile stabilire un attraversamento
+	// (0 è invece un valore valido, ad esempio il P&L di una posizione appena aperta)
+	if previous == nil {
+		return false
+	}
+
+	return (hasUpper && *previous < upper && price >= upper) ||
+		(hasLower && *previous > lower && price <= lower)
+}
+
+// Observe registra il valore (prezzo, valore del portafoglio o P&L) osservato all'istante now
+func (a *Alert) Observe(value float64, now time.Time) {
+	a.CurrentPrice = value
+	a.ObservedAt = &now
+}
+
+// PreviousObservation restituisce l'ultimo valore osservato, nil se non è mai stato osservato
+// o se risale a prima dell'inizio della finestra di attivazione
+func (a *Alert) PreviousObservation() *float64 {
+	if a.ObservedAt == nil || (a.ActiveFrom != nil && a.ObservedAt.Before(*a.ActiveFrom)) {
+		return nil
+	}
+
+	previous := a.CurrentPrice
+	return &previous
+}
+
+// CanRearm indica se un alert ricorrente già scattato può essere riarmato: devono essere trascorsi
+// i minuti di cooldown e, se è impostata un'isteresi, il prezzo deve essere rientrato oltre la banda
+func (a *Alert) CanRearm(price float64, now time.Time) bool {
+	if !a.Recurring || !a.Triggered || a.Exhausted() {
+		return false
+	}
+
+	cooldown := time.Duration(a.CooldownMinutes) * time.Minute
+	if a.NotifiedAt != nil && now.Sub(*a.NotifiedAt) < cooldown {
+		return false
+	}
+
+	if a.HysteresisPercent <= 0 {
+		return true
+	}
+
+	band := a.HysteresisPercent / 100
+	upper, lower, hasUpper, hasLower := a.bounds()
+	if hasUpper && hasLower && upper == lower {
+		// Soglia singola attraversabile in entrambi i versi: basta allontanarsene della banda
+		return math.Abs(price-upper) >= math.Abs(upper)*band
+	}
+
+	// Il valore assoluto rende la banda corretta anche per soglie negative (es. P&L -20%)
+	return (!hasUpper || price <= upper-math.Abs(upper)*band) && (!hasLower || price >= lower+math.Abs(lower)*band)
+}
+
+// Rearm riarma manualmente un alert già scattato. Un alert ricorrente che ha esaurito gli scatti
+// riparte da zero, altrimenti il monitor non potrebbe più farlo scattare.
+func (a *Alert) Rearm() {
+	a.Triggered = false
+	if a.Exhausted() {
+		a.FireCount = 0
+	}
+}
+
+// Exhausted indica se l'alert ha raggiunto il numero massimo di scatti
+func (a *Alert) Exhausted() bool {
+	return a.MaxFires > 0 && a.FireCount >= a.MaxFires
+}
+
+// IsActiveAt indica se la finestra di attivazione dell'alert è già iniziata all'istante now
+func (a *Alert) IsActiveAt(now time.Time) bool {
+	return a.ActiveFrom == nil || !now.Before(*a.ActiveFrom)
+}
+
+// IsExpiredAt indica se l'alert è scaduto all'istante now
+func (a *Alert) IsExpiredAt(now time.Time) bool {
+	return a.Expired || (a.ExpiresAt != nil && !now.Before(*a.ExpiresAt))
+}
+
+// RollingReference ricava il prezzo di 24 ore prima dal prezzo attuale e dalla variazione percentuale nelle 24 ore
+func RollingReference(price, change24h float64) float64 {
+	return price / (1 + change24h/100)
+}
+
+// SetReference imposta il prezzo di riferimento di un alert percentuale in base al tipo di riferimento:
+// il prezzo attuale per "fixed", il prezzo di 24 ore prima (ricavato dalla variazione) per "24h"
+func (a *Alert) SetReference(price float64, change24h *float64) error {
+	if a.Baseline != BaselineRolling {
+		a.ReferencePrice = price
+		return nil
+	}
+
+	if change24h == nil {
+		return i18n.Errorf("validation.change24h_unavailable", a.CryptoID)
+	}
+	a.ReferencePrice = RollingReference(price, *change24h)
+	return nil
+}
+
+// ChangePercent restituisce la variazione percentuale del prezzo rispetto al prezzo di riferimento
+func (a *Alert) ChangePercent(price float64) float64 {
+	if a.ReferencePrice == 0 {
+		return 0
+	}
+	return (price - a.ReferencePrice) / a.ReferencePrice * 100
+}
+
+// CreationWarning restituisce un avviso, nella lingua indicata, se la condizione è già soddisfatta
+// dal prezzo al momento della creazione, altrimenti una stringa vuota
+func (a *Alert) CreationWarning(lang string, price float64) string {
+	if !a.ConditionMet(price) {
+		return ""
+	}
+
+	if a.TriggerMode == TriggerModeLevel {
+		return i18n.T(lang, "alert.warning_already_met_level")
+	}
+	return i18n.T(lang, "alert.warning_already_met_crossing")
 }
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"crypto-tracker/i18n"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipi di evento registrati nella cronologia di un alert
+const (
+	AlertEventTrigger = "trigger" // l'alert è scattato
+	AlertEventReset   = "reset"   // l'alert è stato riarmato (manualmente o automaticamente)
+	AlertEventUpdate  = "update"  // la condizione o le opzioni dell'alert sono state modificate
+	AlertEventExpiry  = "expiry"  // l'alert è scaduto
+)
+
+// Cause di un riarmo registrate nella cronologia
+const (
+	ResetManual    = "manual"    // reset richiesto dall'utente
+	ResetAutomatic = "automatic" // riarmo automatico di un alert ricorrente
+)
+
+// AlertChange è la modifica di un campo di un alert, con i valori prima e dopo.
+// Field è il nome del campo nel catalogo dei messaggi (es. "threshold" per "event.field.threshold").
+type AlertChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// AlertEvent rappresenta un evento nella cronologia di un alert (es. ogni volta che scatta)
+type AlertEvent struct {
+	ID        uint          `gorm:"primaryKey"`
+	AlertID   uint          `gorm:"index;not null"`                // ID dell'alert a cui si riferisce l'evento
+	Type      string        `gorm:"type:varchar(20);not null"`     // Tipo di evento: trigger, reset, update o expiry
+	Price     float64       `gorm:"type:decimal(20,8)"`            // Prezzo osservato al momento dell'evento
+	Currency  string        `gorm:"type:varchar(10);not null"`     // Valuta del prezzo
+	Reason    string        `gorm:"type:varchar(20)"`              // Causa dell'evento (manual o automatic per i riarmi)
+	Changes   []AlertChange `gorm:"type:text;serializer:json"`     // Modifiche applicate (solo eventi update)
+	CreatedAt time.Time     `gorm:"type:timestamp;not null;index"` // Quando si è verificato l'evento
+}
+
+// NewEvent crea un evento della cronologia dell'alert con l'ultimo prezzo osservato;
+// reason è la causa dell'evento, se rilevante (es. ResetManual)
+func (a *Alert) NewEvent(eventType, reason string, at time.Time) AlertEvent {
+	return AlertEvent{
+		AlertID:   a.ID,
+		Type:      eventType,
+		Price:     a.CurrentPrice,
+		Currency:  a.Currency,
+		Reason:    reason,
+		CreatedAt: at,
+	}
+}
+
+// NewUpdateEvent crea l'evento di modifica dell'alert rispetto alla versione before;
+// restituisce false se la condizione e le opzioni non sono cambiate
+func (a *Alert) NewUpdateEvent(before *Alert, at time.Time) (AlertEvent, bool) {
+	changes := Changes(before, a)
+	if len(changes) == 0 {
+		return AlertEvent{}, false
+	}
+
+	event := a.NewEvent(AlertEventUpdate, "", at)
+	event.Changes = changes
+	return event, true
+}
+
+// Describe descrive l'evento nella lingua indicata (es. le modifiche applicate o la causa del riarmo);
+// restituisce una stringa vuota se non c'è nulla da aggiungere al tipo di evento
+func (e *AlertEvent) Describe(lang string) string {
+	if len(e.Changes) > 0 {
+		parts := make([]string, 0, len(e.Changes))
+		for _, change := range e.Changes {
+			parts = append(parts, i18n.T(lang, "event.change", i18n.T(lang, "event.field."+change.Field),
+				describeValue(lang, change.From), describeValue(lang, change.To)))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	if e.Reason != "" {
+		return i18n.T(lang, "event.reason."+e.Reason)
+	}
+	return ""
+}
+
+// describeValue traduce i valori di una modifica che dipendono dalla lingua (sì/no, nessun valore)
+func describeValue(lang, value string) string {
+	switch value {
+	case "":
+		return i18n.T(lang, "event.value_none")
+	case "true":
+		return i18n.T(lang, "event.value_yes")
+	case "false":
+		return i18n.T(lang, "event.value_no")
+	default:
+		return value
+	}
+}
+
+// SaveWithEvents salva l'alert e registra gli eventi nella sua cronologia in un'unica transazione
+func (a *Alert) SaveWithEvents(db *gorm.DB, events ...AlertEvent) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(a).Error; err != nil {
+			return err
+		}
+		for i := range events {
+			events[i].AlertID = a.ID
+			if err := tx.Create(&events[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteWithEvents elimina l'alert insieme alla sua cronologia
+func (a *Alert) DeleteWithEvents(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("alert_id = ?", a.ID).Delete(&AlertEvent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(a).Error
+	})
+}
+
+// Changes restituisce le modifiche alla condizione e alle opzioni tra due versioni di un alert
+// (es. soglia 60000 → 65000, direzione above → cross); nil se non ci sono modifiche
+func Changes(before, after *Alert) []AlertChange {
+	var changes []AlertChange
+	add := func(field string, from, to any) {
+		if fmt.Sprint(from) != fmt.Sprint(to) {
+			changes = append(changes, AlertChange{Field: field, From: fmt.Sprint(from), To: fmt.Sprint(to)})
+		}
+	}
+
+	add("threshold", before.ThresholdPrice, after.ThresholdPrice)
+	add("percent_change", before.PercentChange, after.PercentChange)
+	add("direction", before.Direction, after.Direction)
+	add("trigger_mode", before.TriggerMode, after.TriggerMode)
+	add("recurring", before.Recurring, after.Recurring)
+	add("cooldown", before.CooldownMinutes, after.CooldownMinutes)
+	add("hysteresis", before.HysteresisPercent, after.HysteresisPercent)
+	add("max_fires", before.MaxFires, after.MaxFires)
+	add("active_from", formatEventTime(before.ActiveFrom), formatEventTime(after.ActiveFrom))
+	add("expires_at", formatEventTime(before.ExpiresAt), formatEventTime(after.ExpiresAt))
+
+	return changes
+}
+
+// formatEventTime formatta un istante opzionale per la cronologia (stringa vuota se assente)
+func formatEventTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
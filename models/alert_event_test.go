@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertEventDescribe(t *testing.T) {
+	before := Alert{ThresholdPrice: 60000, Direction: DirectionAbove}
+	after := before
+	after.ThresholdPrice = 65000
+	after.Recurring = true
+	expiresAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	after.ExpiresAt = &expiresAt
+
+	event, changed := after.NewUpdateEvent(&before, time.Now())
+	if !changed || len(event.Changes) != 3 {
+		t.Fatalf("modifiche inattese: %+v", event.Changes)
+	}
+
+	tests := []struct {
+		lang, want string
+	}{
+		{"it", "soglia 60000 → 65000, ricorrente no → sì, scadenza nessuno → 2026-11-01T00:00:00Z"},
+		{"en", "threshold 60000 → 65000, recurring no → yes, expiry none → 2026-11-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		if got := event.Describe(tt.lang); got != tt.want {
+			t.Errorf("Describe(%q) = %q, atteso %q", tt.lang, got, tt.want)
+		}
+	}
+
+	if _, changed := after.NewUpdateEvent(&after, time.Now()); changed {
+		t.Error("nessuna modifica attesa tra due versioni uguali")
+	}
+}
+
+func TestAlertEventDescribeReason(t *testing.T) {
+	alert := Alert{ID: 3}
+
+	tests := []struct {
+		eventType, reason, lang, want string
+	}{
+		{AlertEventReset, ResetAutomatic, "it", "riarmo automatico"},
+		{AlertEventReset, ResetAutomatic, "en", "automatic rearm"},
+		{AlertEventReset, ResetManual, "it", "reset manuale"},
+		{AlertEventReset, ResetManual, "en", "manual reset"},
+		{AlertEventTrigger, "", "en", ""},
+	}
+	for _, tt := range tests {
+		event := alert.NewEvent(tt.eventType, tt.reason, time.Now())
+		if got := event.Describe(tt.lang); got != tt.want {
+			t.Errorf("Describe(%q) di %s/%s = %q, atteso %q", tt.lang, tt.eventType, tt.reason, got, tt.want)
+		}
+	}
+}
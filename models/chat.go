@@ -0,0 +1,75 @@
+package models
+
+import (
+	"crypto-tracker/i18n"
+	"strings"
+	"time"
+)
+
+// DefaultTimezone è il fuso orario delle chat che non ne hanno scelto uno
+const DefaultTimezone = "Europe/Rome"
+
+// Stati di una chat
+const (
+	ChatStatusActive  = "active"  // La chat riceve i messaggi del bot
+	ChatStatusBlocked = "blocked" // L'utente ha bloccato il bot (o lo ha rimosso dal gruppo)
+)
+
+// Chat rappresenta una chat Telegram che ha usato il bot, con i suoi dati e le sue preferenze.
+// Viene aggiornata a ogni update ricevuto dalla chat.
+type Chat struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement:false"`                    // ID della chat Telegram
+	Type        string    `gorm:"type:varchar(20)"`                                  // Tipo di chat: private, group, supergroup o channel
+	Username    string    `gorm:"type:varchar(64);index"`                            // Username Telegram, senza @
+	FirstName   string    `gorm:"type:varchar(128)"`                                 // Nome dell'utente (chat private)
+	LastName    string    `gorm:"type:varchar(128)"`                                 // Cognome dell'utente (chat private)
+	Title       string    `gorm:"type:varchar(255)"`                                 // Titolo del gruppo o del canale
+	Language    string    `gorm:"type:varchar(5);not null;default:'it'"`             // Lingua dei messaggi (es. "it" o "en")
+	Timezone    string    `gorm:"type:varchar(64);not null;default:'Europe/Rome'"`   // Fuso orario IANA delle date mostrate (es. "Europe/London")
+	Status      string    `gorm:"type:varchar(10);not null;default:'active';index"`  // Stato: active o blocked
+	FirstSeenAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"` // Primo update ricevuto
+	LastSeenAt  time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"` // Ultimo update ricevuto
+	CreatedAt   time.Time `gorm:"type:timestamp;not null"`
+	UpdatedAt   time.Time `gorm:"type:timestamp;not null"`
+}
+
+// ParseChatStatus verifica lo stato di una chat
+func ParseChatStatus(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case ChatStatusActive:
+		return ChatStatusActive, nil
+	case ChatStatusBlocked:
+		return ChatStatusBlocked, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_chat_status", value)
+	}
+}
+
+// ParseTimezone verifica che il fuso orario esista nel database IANA (es. "Europe/London" o "UTC")
+func ParseTimezone(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	// "Local" dipenderebbe dalla configurazione del server
+	if value == "" || value == "Local" {
+		return "", i18n.Errorf("validation.invalid_timezone", value)
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return "", i18n.Errorf("validation.invalid_timezone", value)
+	}
+	return loc.String(), nil
+}
+
+// Location restituisce il fuso orario della chat, quello predefinito se non impostato (UTC se non valido)
+func (c *Chat) Location() *time.Location {
+	timezone := c.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Coin rappresenta una criptovaluta del registro locale, copiato dall'elenco di CoinGecko
+type Coin struct {
+	ID            string    `gorm:"primaryKey;type:varchar(100)"`          // ID CoinGecko (es. "bitcoin")
+	Symbol        string    `gorm:"type:varchar(50);not null;index"`       // Simbolo in minuscolo (es. "btc")
+	Name          string    `gorm:"type:varchar(200);not null;index"`      // Nome (es. "Bitcoin")
+	MarketCap     float64   `gorm:"type:decimal(24,2);not null;default:0"` // Capitalizzazione in USD (0 se non nota)
+	MarketCapRank int       `gorm:"not null;default:0"`                    // Posizione per capitalizzazione (0 se non nota)
+	UpdatedAt     time.Time `gorm:"type:timestamp;not null;index"`         // Ultimo aggiornamento dal registro
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"crypto-tracker/i18n"
+	"strings"
+	"time"
+)
+
+// Frequenze dei digest periodici
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestSchedule rappresenta l'iscrizione di una chat a un riepilogo periodico.
+// Il prossimo invio è salvato nel database, così la pianificazione sopravvive ai riavvii.
+type DigestSchedule struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserChatID int64      `gorm:"not null;uniqueIndex"`                      // ID della chat Telegram (un digest per chat)
+	Frequency  string     `gorm:"type:varchar(10);not null;default:'daily'"` // Frequenza: daily o weekly
+	Hour       int        `gorm:"not null;default:8"`                        // Ora dell'invio nel fuso orario della chat
+	Minute     int        `gorm:"not null;default:0"`                        // Minuto dell'invio
+	Weekday    int        `gorm:"not null;default:1"`                        // Giorno della settimana (0 = domenica, solo per weekly)
+	Currency   string     `gorm:"type:varchar(10);not null;default:'usd'"`   // Valuta dei prezzi nel digest
+	NextRunAt  time.Time  `gorm:"type:timestamp;not null;index"`             // Prossimo invio previsto (UTC)
+	LastRunAt  *time.Time `gorm:"type:timestamp"`                            // Ultimo invio effettuato
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp;not null"`
+}
+
+// ParseDigestFrequency converte la frequenza testuale nel valore salvato
+func ParseDigestFrequency(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case DigestDaily, "giornaliero":
+		return DigestDaily, nil
+	case DigestWeekly, "settimanale":
+		return DigestWeekly, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_frequency", value)
+	}
+}
+
+// ParseClock interpreta un orario nel formato HH:MM
+func ParseClock(value string) (hour, minute int, err error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, i18n.Errorf("validation.invalid_clock", value)
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// NextRun restituisce il primo invio previsto successivo ad after, in UTC. L'orario del digest
+// è interpretato nel fuso orario loc, quello della chat (vedi Chat.Location).
+func (s *DigestSchedule) NextRun(after time.Time, loc *time.Location) time.Time {
+	local := after.In(loc)
+
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.Hour, s.Minute, 0, 0, loc)
+	step := 1
+	if s.Frequency == DigestWeekly {
+		next = next.AddDate(0, 0, (s.Weekday-int(next.Weekday())+7)%7)
+		step = 7
+	}
+
+	// AddDate mantiene l'orario locale anche nei giorni di cambio dell'ora legale
+	for !next.After(after) {
+		next = next.AddDate(0, 0, step)
+	}
+
+	return next.UTC()
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestScheduleNextRun(t *testing.T) {
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule DigestSchedule
+		timezone string // fuso orario della chat, quello predefinito se vuoto
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:     "giornaliero nello stesso giorno",
+			schedule: DigestSchedule{Frequency: DigestDaily, Hour: 8},
+			after:    utc(time.January, 10, 6, 0),
+			want:     utc(time.January, 10, 7, 0),
+		},
+		{
+			name:     "orario già passato",
+			schedule: DigestSchedule{Frequency: DigestDaily, Hour: 8},
+			after:    utc(time.January, 10, 7, 0),
+			want:     utc(time.January, 11, 7, 0),
+		},
+		{
+			// Il 29 marzo l'Italia passa all'ora legale: le 08:00 diventano le 06:00 UTC
+			name:     "passaggio all'ora legale",
+			schedule: DigestSchedule{Frequency: DigestDaily, Hour: 8},
+			after:    utc(time.March, 28, 7, 30),
+			want:     utc(time.March, 29, 6, 0),
+		},
+		{
+			// Il 25 ottobre si torna all'ora solare: le 08:00 diventano le 07:00 UTC
+			name:     "ritorno all'ora solare",
+			schedule: DigestSchedule{Frequency: DigestDaily, Hour: 8},
+			after:    utc(time.October, 24, 7, 0),
+			want:     utc(time.October, 25, 7, 0),
+		},
+		{
+			name:     "settimanale attraverso il cambio d'ora",
+			schedule: DigestSchedule{Frequency: DigestWeekly, Weekday: int(time.Monday), Hour: 8},
+			after:    utc(time.October, 23, 12, 0),
+			want:     utc(time.October, 26, 7, 0),
+		},
+		{
+			name:     "settimanale nel giorno indicato dopo l'orario",
+			schedule: DigestSchedule{Frequency: DigestWeekly, Weekday: int(time.Monday), Hour: 8, Minute: 30},
+			after:    utc(time.October, 26, 8, 0),
+			want:     utc(time.November, 2, 7, 30),
+		},
+		{
+			name:     "fuso orario non valido",
+			schedule: DigestSchedule{Frequency: DigestDaily, Hour: 8},
+			timezone: "Mars/Olympus",
+			after:    utc(time.March, 28, 9, 0),
+			want:     utc(time.March, 29, 8, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat := Chat{Timezone: tt.timezone}
+			if got := tt.schedule.NextRun(tt.after, chat.Location()); !got.Equal(tt.want) {
+				t.Errorf("NextRun(%s) = %s, atteso %s", tt.after.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"crypto-tracker/i18n"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tipi di transazione del portafoglio
+const (
+	TransactionBuy  = "buy"
+	TransactionSell = "sell"
+)
+
+// quantityTolerance è la differenza trascurabile tra quantità dovuta agli arrotondamenti
+const quantityTolerance = 1e-12
+
+// ErrInsufficientQuantity indica una vendita superiore alla quantità posseduta
+var ErrInsufficientQuantity = i18n.Errorf("portfolio.insufficient_quantity")
+
+// Holding rappresenta la posizione di un utente su una criptovaluta, con il costo medio di carico
+type Holding struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserChatID  int64     `gorm:"not null;uniqueIndex:idx_holdings_owner_coin,priority:1"`                                           // ID della chat Telegram del proprietario
+	CryptoID    string    `gorm:"column:cryptocurrency_id;type:varchar(50);not null;uniqueIndex:idx_holdings_owner_coin,priority:2"` // ID della criptovaluta
+	Currency    string    `gorm:"type:varchar(10);not null;default:'usd'"`                                                           // Valuta di costo e valutazione
+	Quantity    float64   `gorm:"type:decimal(28,12);not null;default:0"`                                                            // Quantità posseduta
+	CostBasis   float64   `gorm:"type:decimal(20,8);not null;default:0"`                                                             // Costo complessivo della quantità posseduta
+	RealizedPnL float64   `gorm:"type:decimal(20,8);not null;default:0"`                                                             // Profitto o perdita realizzati con le vendite
+	CreatedAt   time.Time `gorm:"type:timestamp;not null"`
+	UpdatedAt   time.Time `gorm:"type:timestamp;not null"`
+}
+
+// Transaction rappresenta un acquisto o una vendita registrati nel portafoglio
+type Transaction struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserChatID int64     `gorm:"index;not null"`                                           // ID della chat Telegram del proprietario
+	CryptoID   string    `gorm:"column:cryptocurrency_id;type:varchar(50);not null;index"` // ID della criptovaluta
+	Type       string    `gorm:"type:varchar(10);not null"`                                // Tipo: buy o sell
+	Quantity   float64   `gorm:"type:decimal(28,12);not null"`                             // Quantità acquistata o venduta
+	Price      float64   `gorm:"type:decimal(20,8);not null"`                              // Prezzo unitario
+	Currency   string    `gorm:"type:varchar(10);not null;default:'usd'"`                  // Valuta del prezzo
+	ExecutedAt time.Time `gorm:"type:timestamp;not null;index"`                            // Quando è stata eseguita l'operazione
+	CreatedAt  time.Time `gorm:"type:timestamp;not null"`
+}
+
+// ParseTransactionType converte il tipo testuale nel valore salvato
+func ParseTransactionType(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case TransactionBuy:
+		return TransactionBuy, nil
+	case TransactionSell:
+		return TransactionSell, nil
+	default:
+		return "", i18n.Errorf("validation.invalid_transaction_type", value)
+	}
+}
+
+// AverageCost restituisce il costo medio unitario della posizione
+func (h *Holding) AverageCost() float64 {
+	if h.Quantity == 0 {
+		return 0
+	}
+	return h.CostBasis / h.Quantity
+}
+
+// Apply aggiorna la posizione con una transazione usando il metodo del costo medio:
+// un acquisto aumenta quantità e costo, una vendita riduce il costo in proporzione
+// e registra il profitto realizzato rispetto al costo medio
+func (h *Holding) Apply(tx Transaction) error {
+	switch tx.Type {
+	case TransactionBuy:
+		h.Quantity += tx.Quantity
+		h.CostBasis += tx.Quantity * tx.Price
+	case TransactionSell:
+		if tx.Quantity > h.Quantity+quantityTolerance {
+			return i18n.Errorf("portfolio.insufficient_quantity_detail", ErrInsufficientQuantity, h.Quantity, h.CryptoID)
+		}
+		quantity := min(tx.Quantity, h.Quantity)
+		cost := quantity * h.AverageCost()
+		h.RealizedPnL += quantity*tx.Price - cost
+		h.Quantity -= quantity
+		h.CostBasis -= cost
+		if h.Quantity <= quantityTolerance {
+			h.Quantity, h.CostBasis = 0, 0
+		}
+	default:
+		return fmt.Errorf("tipo di transazione non valido: %q", tx.Type)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// PriceSample rappresenta un campione della serie storica dei prezzi di una criptovaluta.
+// I campioni recenti sono singole osservazioni (Resolution = 0, Open = High = Low = Close);
+// quelli più vecchi vengono aggregati in intervalli di durata Resolution.
+type PriceSample struct {
+	ID         uint      `gorm:"primaryKey"`
+	CoinID     string    `gorm:"type:varchar(50);not null;index:idx_price_samples_lookup,priority:1"` // ID della criptovaluta (es. "bitcoin")
+	Currency   string    `gorm:"type:varchar(10);not null;index:idx_price_samples_lookup,priority:2"` // Valuta di quotazione
+	SampledAt  time.Time `gorm:"type:timestamp;not null;index:idx_price_samples_lookup,priority:3"`   // Inizio dell'intervallo (o istante dell'osservazione)
+	Resolution int       `gorm:"not null;default:0;index"`                                            // Secondi coperti dal campione (0 = singola osservazione)
+	Open       float64   `gorm:"type:decimal(20,8);not null"`                                         // Primo prezzo dell'intervallo
+	High       float64   `gorm:"type:decimal(20,8);not null"`                                         // Prezzo massimo dell'intervallo
+	Low        float64   `gorm:"type:decimal(20,8);not null"`                                         // Prezzo minimo dell'intervallo
+	Close      float64   `gorm:"type:decimal(20,8);not null"`                                         // Ultimo prezzo dell'intervallo
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// WatchlistItem rappresenta una criptovaluta nella watchlist di un utente
+type WatchlistItem struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserChatID int64     `gorm:"not null;uniqueIndex:idx_watchlist_owner_coin,priority:1"`                                           // ID della chat Telegram del proprietario
+	CryptoID   string    `gorm:"column:cryptocurrency_id;type:varchar(50);not null;uniqueIndex:idx_watchlist_owner_coin,priority:2"` // ID della criptovaluta
+	CreatedAt  time.Time `gorm:"type:timestamp;not null"`
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"crypto-tracker/controllers"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupAdminRoutes configura le routes di amministrazione, protette dal token indicato
+func SetupAdminRoutes(router *gin.Engine, db *gorm.DB, token string) {
+	adminRoutes := router.Group("/admin")
+	adminRoutes.Use(controllers.RequireAdminToken(token))
+	{
+		adminRoutes.GET("/chats", controllers.GetChats(db))
+		adminRoutes.GET("/chats/:id", controllers.GetChat(db))
+	}
+}
@@ -2,20 +2,23 @@ package routes
 
 import (
 	"crypto-tracker/controllers"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/pricing"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // SetupAlertRoutes configura tutte le routes per gli alert
-func SetupAlertRoutes(router *gin.Engine, db *gorm.DB) {
+func SetupAlertRoutes(router *gin.Engine, db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) {
 	alertRoutes := router.Group("/alerts")
 	{
 		alertRoutes.GET("/", controllers.GetAlerts(db))
 		alertRoutes.GET("/active", controllers.GetActiveAlerts(db))
 		alertRoutes.GET("/:id", controllers.GetAlert(db))
-		alertRoutes.POST("/", controllers.CreateAlert(db))
-		alertRoutes.PUT("/:id", controllers.UpdateAlert(db))
+		alertRoutes.GET("/:id/events", controllers.GetAlertEvents(db))
+		alertRoutes.POST("/", controllers.CreateAlert(db, prices, registry))
+		alertRoutes.PUT("/:id", controllers.UpdateAlert(db, prices))
 		alertRoutes.DELETE("/:id", controllers.DeleteAlert(db))
 	}
 }
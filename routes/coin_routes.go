@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"crypto-tracker/controllers"
+	"crypto-tracker/services/coins"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCoinRoutes configura le routes per il registro delle criptovalute
+func SetupCoinRoutes(router *gin.Engine, registry *coins.Registry) {
+	coinRoutes := router.Group("/coins")
+	{
+		coinRoutes.GET("/search", controllers.SearchCoins(registry))
+	}
+}
@@ -2,13 +2,19 @@ package routes
 
 import (
 	"crypto-tracker/controllers"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/history"
+	"crypto-tracker/services/pricing"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // SetupCryptoRoutes configura le routes per le operazioni relative alle criptovalute
-func SetupCryptoRoutes(router *gin.Engine, db *gorm.DB) {
+func SetupCryptoRoutes(router *gin.Engine, db *gorm.DB, prices pricing.PriceProvider, store *history.Store, registry *coins.Registry) {
 	// Endpoint per ottenere il prezzo di una criptovaluta
-	router.GET("/price/:id", controllers.GetCryptoPriceHandler(db))
+	router.GET("/price/:id", controllers.GetCryptoPriceHandler(db, prices, registry))
+
+	// Endpoint per lo storico dei prezzi (punti o candele OHLC)
+	router.GET("/price/:id/history", controllers.GetPriceHistoryHandler(store, registry))
 }
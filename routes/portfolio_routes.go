@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"crypto-tracker/controllers"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/pricing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupPortfolioRoutes configura le routes per il portafoglio
+func SetupPortfolioRoutes(router *gin.Engine, db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) {
+	portfolioRoutes := router.Group("/portfolio")
+	{
+		portfolioRoutes.GET("/", controllers.GetPortfolio(db, prices))
+		portfolioRoutes.GET("/transactions", controllers.GetTransactions(db, registry))
+		portfolioRoutes.POST("/transactions", controllers.CreateTransaction(db, prices, registry))
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"crypto-tracker/controllers"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/pricing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupWatchlistRoutes configura le routes per le watchlist
+func SetupWatchlistRoutes(router *gin.Engine, db *gorm.DB, prices pricing.PriceProvider, registry *coins.Registry) {
+	watchlistRoutes := router.Group("/watchlist")
+	{
+		watchlistRoutes.GET("/", controllers.GetWatchlist(db, prices))
+		watchlistRoutes.POST("/", controllers.AddToWatchlist(db, prices, registry))
+		watchlistRoutes.DELETE("/:id", controllers.RemoveFromWatchlist(db, registry))
+	}
+}
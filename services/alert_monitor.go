@@ -1,29 +1,40 @@
 package services
 
 import (
-	"crypto-tracker/controllers"
+	"context"
 	"crypto-tracker/models"
+	"crypto-tracker/services/history"
+	"crypto-tracker/services/portfolio"
+	"crypto-tracker/services/pricing"
+	"errors"
 	"log"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// priceRequestTimeout limita la durata di ogni richiesta di prezzo del monitor
+const priceRequestTimeout = 30 * time.Second
+
 type AlertMonitor struct {
 	db               *gorm.DB
+	prices           pricing.PriceProvider
 	interval         time.Duration
 	stopChan         chan struct{}
 	telegramNotifyCh chan *models.Alert // Canale per notifiche Telegram
+	history          *history.Store     // Storico dei prezzi (opzionale)
 }
 
 // NewAlertMonitor crea una nuova istanza del monitor degli alert
-func NewAlertMonitor(db *gorm.DB, interval time.Duration) *AlertMonitor {
+func NewAlertMonitor(db *gorm.DB, prices pricing.PriceProvider, interval time.Duration) *AlertMonitor {
 	if interval < time.Second {
 		interval = time.Minute // Valore di default
 	}
 
 	return &AlertMonitor{
 		db:               db,
+		prices:           prices,
 		interval:         interval,
 		stopChan:         make(chan struct{}),
 		telegramNotifyCh: make(chan *models.Alert, 100), // Buffer per le notifiche
@@ -35,6 +46,11 @@ func (am *AlertMonitor) SetTelegramNotificationChannel(ch chan *models.Alert) {
 	am.telegramNotifyCh = ch
 }
 
+// SetHistory imposta l'archivio in cui salvare i prezzi ottenuti a ogni controllo
+func (am *AlertMonitor) SetHistory(store *history.Store) {
+	am.history = store
+}
+
 // Start avvia il monitoraggio in background
 func (am *AlertMonitor) Start() {
 	log.Printf("[AlertMonitor] Avvio del monitoraggio (intervallo: %v)", am.interval)
@@ -68,8 +84,15 @@ func (am *AlertMonitor) Stop() {
 func (am *AlertMonitor) checkAlerts() {
 	log.Println("[AlertMonitor] Controllo degli alert attivi...")
 
+	now := time.Now().UTC()
+	am.expireAlerts(now)
+
+	// Oltre agli alert non ancora scattati servono quelli ricorrenti che possono essere riarmati;
+	// gli alert scaduti o la cui finestra di attivazione non è ancora iniziata vengono esclusi
 	var activeAlerts []models.Alert
-	if err := am.db.Where("triggered = ?", false).Find(&activeAlerts).Error; err != nil {
+	if err := am.db.Where("triggered = ? OR (recurring = ? AND (max_fires = 0 OR fire_count < max_fires))", false, true).
+		Where("expired = ? AND (active_from IS NULL OR active_from <= ?)", false, now).
+		Find(&activeAlerts).Error; err != nil {
 		log.Printf("[AlertMonitor] Errore nel recupero degli alert: %v", err)
 		return
 	}
@@ -79,48 +102,224 @@ func (am *AlertMonitor) checkAlerts() {
 		return
 	}
 
-	// Controlla ogni alert in sequenza
-	// Non c'è vero bisogno di parallelizzare questa operazione
-	// a meno che non si abbiano migliaia di alert da controllare
+	// Raccogli gli ID distinti delle criptovalute da monitorare, per valuta di quotazione
+	coinIDs := make(map[string][]string)
+	addCoin := func(currency, coinID string) {
+		currency = pricing.NormalizeCurrency(currency)
+		if !slices.Contains(coinIDs[currency], coinID) {
+			coinIDs[currency] = append(coinIDs[currency], coinID)
+		}
+	}
+
+	var portfolioChats []int64
+	for _, alert := range activeAlerts {
+		if alert.IsPortfolio() {
+			if !slices.Contains(portfolioChats, alert.UserChatID) {
+				portfolioChats = append(portfolioChats, alert.UserChatID)
+			}
+			continue
+		}
+		addCoin(alert.Currency, alert.CryptoID)
+	}
+
+	// Per gli alert sul portafoglio servono anche i prezzi di tutte le posizioni dei proprietari
+	holdings, err := portfolio.HoldingsByChat(am.db, portfolioChats)
+	if err != nil {
+		log.Printf("[AlertMonitor] Errore nel recupero delle posizioni: %v", err)
+	}
+	for _, chatHoldings := range holdings {
+		for _, holding := range chatHoldings {
+			if holding.Quantity > 0 {
+				addCoin(holding.Currency, holding.CryptoID)
+			}
+		}
+	}
+
+	// Ottieni tutti i prezzi in un'unica istantanea (una richiesta per valuta)
+	snapshot, ok := am.fetchSnapshot(coinIDs)
+	if !ok {
+		return
+	}
+
+	am.recordHistory(snapshot)
+
+	// Valuta i portafogli con la stessa istantanea di prezzi
+	snapshotPrices := make(map[string]map[string]float64, len(snapshot))
+	for currency, quotes := range snapshot {
+		snapshotPrices[currency] = make(map[string]float64, len(quotes))
+		for id, quote := range quotes {
+			snapshotPrices[currency][id] = quote.Price
+		}
+	}
+	summaries := make(map[int64]portfolio.Summary, len(holdings))
+	for chatID, chatHoldings := range holdings {
+		summaries[chatID] = portfolio.Evaluate(chatHoldings, snapshotPrices)
+	}
+
+	// Valuta ogni alert usando la stessa istantanea di prezzi
 	for i := range activeAlerts {
-		if err := am.processSingleAlert(&activeAlerts[i]); err != nil {
-			log.Printf("[AlertMonitor] Errore per alert ID %d: %v", activeAlerts[i].ID, err)
+		alert := &activeAlerts[i]
+
+		var quote pricing.Quote
+		if alert.IsPortfolio() {
+			// Per gli alert sul portafoglio il valore osservato è il valore totale o il P&L della posizione
+			value, ok := summaries[alert.UserChatID].AlertMetric(alert)
+			if !ok {
+				log.Printf("[AlertMonitor] Portafoglio non valutabile per l'alert ID %d (%s)", alert.ID, alert.Type)
+				continue
+			}
+			quote = pricing.Quote{CoinID: alert.CryptoID, Currency: alert.Currency, Price: value, AsOf: now}
+		} else {
+			var ok bool
+			if quote, ok = snapshot[pricing.NormalizeCurrency(alert.Currency)][alert.CryptoID]; !ok {
+				log.Printf("[AlertMonitor] Prezzo non disponibile per %s in %s (alert ID %d)", alert.CryptoID, alert.Currency, alert.ID)
+				continue
+			}
+		}
+
+		if err := am.processSingleAlert(alert, quote); err != nil {
+			log.Printf("[AlertMonitor] Errore per alert ID %d: %v", alert.ID, err)
 		}
 	}
 }
 
-// processSingleAlert verifica e aggiorna un singolo alert
-func (am *AlertMonitor) processSingleAlert(alert *models.Alert) error {
-	// Ottieni il prezzo corrente
-	price, err := controllers.GetPriceUSD(alert.CryptoID)
-	if err != nil {
-		return err
+// fetchSnapshot ottiene i prezzi delle criptovalute indicate per valuta di quotazione, con una
+// richiesta per valuta; restituisce false se la sorgente è temporaneamente esclusa e il ciclo va saltato
+func (am *AlertMonitor) fetchSnapshot(coinIDs map[string][]string) (map[string]map[string]pricing.Quote, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	snapshot := make(map[string]map[string]pricing.Quote, len(coinIDs))
+	for currency, ids := range coinIDs {
+		quotes, err := pricing.GetQuotes(ctx, am.prices, ids, currency)
+		if errors.Is(err, pricing.ErrCircuitOpen) {
+			log.Println("[AlertMonitor] Sorgente prezzi temporaneamente non disponibile, salto questo ciclo")
+			return nil, false
+		}
+		if err != nil {
+			log.Printf("[AlertMonitor] Errore nel recupero dei prezzi in %s per %d criptovalute: %v", currency, len(ids), err)
+			continue
+		}
+		snapshot[currency] = quotes
 	}
 
-	// Aggiorna il prezzo corrente
-	alert.CurrentPrice = price
+	return snapshot, true
+}
+
+// processSingleAlert verifica e aggiorna un singolo alert data la quotazione corrente
+func (am *AlertMonitor) processSingleAlert(alert *models.Alert, quote pricing.Quote) error {
 	now := time.Now().UTC() // Usa UTC per i timestamp nel database
+	events, fired := evaluateAlert(alert, quote, now)
+	if err := alert.SaveWithEvents(am.db, events...); err != nil {
+		return err
+	}
+
+	// Invia notifica Telegram per l'alert appena triggerato
+	if fired {
+		am.sendTelegramNotification(alert)
+	}
+	return nil
+}
+
+// evaluateAlert applica all'alert la quotazione corrente all'istante now: aggiorna l'ultimo valore
+// osservato, riarma gli alert ricorrenti e verifica la condizione. Restituisce gli eventi da registrare
+// nella cronologia e se l'alert è appena scattato.
+func evaluateAlert(alert *models.Alert, quote pricing.Quote, now time.Time) ([]models.AlertEvent, bool) {
+	price := quote.Price
+
+	// Per gli alert percentuali sulle 24 ore il riferimento segue il prezzo di 24 ore prima
+	if alert.Type == models.AlertTypePercent && alert.Baseline == models.BaselineRolling {
+		if quote.Change24h == nil {
+			log.Printf("[AlertMonitor] Variazione 24h non disponibile per %s, uso l'ultimo riferimento (alert ID %d)", alert.CryptoID, alert.ID)
+		} else {
+			alert.ReferencePrice = models.RollingReference(price, *quote.Change24h)
+		}
+	}
+
+	// Aggiorna il prezzo corrente, conservando l'osservazione precedente. Alla prima osservazione
+	// dopo l'inizio della finestra di attivazione il prezzo salvato risale alla creazione e non
+	// può essere usato per rilevare un attraversamento.
+	previous := alert.PreviousObservation()
+	alert.Observe(price, now)
 	alert.UpdatedAt = now
 
-	// Salva le vecchie informazioni per decidere se inviare notifiche
-	wasTriggeredBefore := alert.Triggered
+	// Gli alert ricorrenti già scattati vengono riarmati dopo il cooldown e l'eventuale isteresi
+	var events []models.AlertEvent
+	if alert.CanRearm(price, now) {
+		log.Printf("[AlertMonitor] Alert ID %d riarmato (scattato %d volte)", alert.ID, alert.FireCount)
+		alert.Triggered = false
+		events = append(events, alert.NewEvent(models.AlertEventReset, models.ResetAutomatic, now))
+	}
+
+	// Verifica la condizione di trigger secondo la direzione dell'alert; un alert che ha esaurito
+	// gli scatti non scatta più anche se non risulta triggerato
+	if alert.Triggered || alert.Exhausted() || !alert.IsTriggeredBy(previous, price) {
+		return events, false
+	}
+
+	log.Printf("[AlertMonitor] ALERT TRIGGERATO! ID: %d, Crypto: %s, Tipo: %s, Direzione: %s, Soglia: %.2f, Variazione: %.2f%%, Prezzo: %.2f",
+		alert.ID, alert.CryptoID, alert.Type, alert.Direction, alert.ThresholdPrice, alert.ChangePercent(price), price)
+
+	alert.Triggered = true
+	alert.NotifiedAt = &now
+	alert.FireCount++
+
+	// Registra lo scatto nella cronologia
+	events = append(events, alert.NewEvent(models.AlertEventTrigger, "", now))
+	return events, true
+}
 
-	// Verifica la condizione di trigger
-	if price >= alert.ThresholdPrice {
-		log.Printf("[AlertMonitor] ALERT TRIGGERATO! ID: %d, Crypto: %s, Soglia: %.2f, Prezzo: %.2f",
-			alert.ID, alert.CryptoID, alert.ThresholdPrice, price)
+// recordHistory salva l'istantanea dei prezzi nello storico, se configurato
+func (am *AlertMonitor) recordHistory(snapshot map[string]map[string]pricing.Quote) {
+	if am.history == nil {
+		return
+	}
 
-		alert.Triggered = true
-		alert.NotifiedAt = &now
+	var quotes []pricing.Quote
+	for _, byCoin := range snapshot {
+		for _, quote := range byCoin {
+			quotes = append(quotes, quote)
+		}
 	}
 
-	// Invia notifica Telegram se l'alert è stato appena triggerato
-	if alert.Triggered && !wasTriggeredBefore {
-		am.sendTelegramNotification(alert)
+	if err := am.history.Record(quotes...); err != nil {
+		log.Printf("[AlertMonitor] Errore nel salvataggio dello storico prezzi: %v", err)
+	}
+}
+
+// expireAlerts marca come scaduti gli alert oltre la scadenza e avvisa i proprietari
+// di quelli scaduti senza essere mai scattati
+func (am *AlertMonitor) expireAlerts(now time.Time) {
+	var expiring []models.Alert
+	if err := am.db.Where("expired = ? AND expires_at IS NOT NULL AND expires_at <= ?", false, now).
+		Find(&expiring).Error; err != nil {
+		log.Printf("[AlertMonitor] Errore nel recupero degli alert scaduti: %v", err)
+		return
 	}
 
-	// Salva nel database
-	return am.db.Save(alert).Error
+	for i := range expiring {
+		alert := &expiring[i]
+		event, notify := expireAlert(alert, now)
+
+		if err := alert.SaveWithEvents(am.db, event); err != nil {
+			log.Printf("[AlertMonitor] Errore nella scadenza dell'alert ID %d: %v", alert.ID, err)
+			continue
+		}
+
+		log.Printf("[AlertMonitor] Alert ID %d scaduto (scattato %d volte)", alert.ID, alert.FireCount)
+		if notify {
+			am.sendTelegramNotification(alert)
+		}
+	}
+}
+
+// expireAlert marca l'alert come scaduto all'istante now. Restituisce l'evento da registrare
+// nella cronologia e se il proprietario va avvisato, cioè se l'alert non è mai scattato.
+func expireAlert(alert *models.Alert, now time.Time) (models.AlertEvent, bool) {
+	alert.Expired = true
+	alert.UpdatedAt = now
+
+	return alert.NewEvent(models.AlertEventExpiry, "", now), !alert.Triggered && alert.FireCount == 0
 }
 
 // sendTelegramNotification invia una notifica Telegram
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/pricing/pricingtest"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// monitorTest esegue i controlli del monitor su un insieme di alert con una sorgente di prezzi finta,
+// senza database: a ogni passo il prezzo viene impostato sulla sorgente e ogni alert valutato
+type monitorTest struct {
+	t       *testing.T
+	prices  *pricingtest.Provider
+	monitor *AlertMonitor
+	now     time.Time
+}
+
+func newMonitorTest(t *testing.T) *monitorTest {
+	prices := pricingtest.New("fake")
+	return &monitorTest{
+		t:       t,
+		prices:  prices,
+		monitor: NewAlertMonitor(nil, prices, time.Minute),
+		now:     time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// tick avanza il tempo di d, imposta il prezzo della criptovaluta dell'alert e lo valuta,
+// restituendo gli eventi registrati e se l'alert è scattato
+func (m *monitorTest) tick(alert *models.Alert, d time.Duration, price float64) ([]models.AlertEvent, bool) {
+	m.t.Helper()
+
+	m.now = m.now.Add(d)
+	m.prices.Set(alert.CryptoID, alert.Currency, price)
+
+	snapshot, ok := m.monitor.fetchSnapshot(map[string][]string{alert.Currency: {alert.CryptoID}})
+	if !ok {
+		m.t.Fatal("istantanea dei prezzi non disponibile")
+	}
+	quote, ok := snapshot[alert.Currency][alert.CryptoID]
+	if !ok {
+		m.t.Fatalf("prezzo di %s non disponibile", alert.CryptoID)
+	}
+
+	return evaluateAlert(alert, quote, m.now)
+}
+
+// expectFires verifica, passo dopo passo, quali prezzi fanno scattare l'alert
+func (m *monitorTest) expectFires(alert *models.Alert, d time.Duration, steps []float64, fires []bool) {
+	m.t.Helper()
+
+	for i, price := range steps {
+		if _, fired := m.tick(alert, d, price); fired != fires[i] {
+			m.t.Fatalf("passo %d (prezzo %v): scattato = %v, atteso %v", i, price, fired, fires[i])
+		}
+	}
+}
+
+func TestMonitorTriggersAtThreshold(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := &models.Alert{ID: 1, CryptoID: "bitcoin", Currency: "usd", ThresholdPrice: 100}
+
+	// L'alert scatta una sola volta, quando il prezzo raggiunge la soglia
+	m.expectFires(alert, time.Minute, []float64{95, 100, 110, 90}, []bool{false, true, false, false})
+	if !alert.Triggered || alert.NotifiedAt == nil || !alert.NotifiedAt.Equal(m.now.Add(-2*time.Minute)) {
+		t.Fatalf("stato inatteso: triggered %v, notificato %v", alert.Triggered, alert.NotifiedAt)
+	}
+	if alert.CurrentPrice != 90 || !alert.UpdatedAt.Equal(m.now) {
+		t.Fatalf("ultimo prezzo = %v alle %v, atteso 90 alle %v", alert.CurrentPrice, alert.UpdatedAt, m.now)
+	}
+}
+
+func TestMonitorDirections(t *testing.T) {
+	m := newMonitorTest(t)
+
+	below := &models.Alert{ID: 3, CryptoID: "bitcoin", Currency: "usd", Direction: models.DirectionBelow, ThresholdPrice: 100}
+	m.expectFires(below, time.Minute, []float64{110, 105, 100}, []bool{false, false, true})
+
+	// Un attraversamento richiede un'osservazione precedente, in entrambi i versi
+	cross := &models.Alert{ID: 4, CryptoID: "bitcoin", Currency: "usd", Direction: models.DirectionCross, ThresholdPrice: 100}
+	m.expectFires(cross, time.Minute, []float64{110, 105, 99}, []bool{false, false, true})
+
+	crossUp := &models.Alert{ID: 5, CryptoID: "bitcoin", Currency: "usd", Direction: models.DirectionCross, ThresholdPrice: 100}
+	m.expectFires(crossUp, time.Minute, []float64{90, 100}, []bool{false, true})
+}
+
+func TestMonitorCrossingNeedsPreviousObservation(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := &models.Alert{ID: 6, CryptoID: "bitcoin", Currency: "usd", Direction: models.DirectionAbove,
+		TriggerMode: models.TriggerModeCrossing, ThresholdPrice: 100}
+
+	// Il prezzo è già oltre la soglia alla prima osservazione: non c'è stato un attraversamento
+	m.expectFires(alert, time.Minute, []float64{105, 110, 95, 101}, []bool{false, false, false, true})
+}
+
+func TestMonitorLevelMode(t *testing.T) {
+	m := newMonitorTest(t)
+
+	// In modalità level basta il livello, anche senza osservazioni precedenti
+	above := &models.Alert{ID: 7, CryptoID: "bitcoin", Currency: "usd", Direction: models.DirectionAbove,
+		TriggerMode: models.TriggerModeLevel, ThresholdPrice: 100}
+	m.expectFires(above, time.Minute, []float64{105}, []bool{true})
+
+	below := &models.Alert{ID: 8, CryptoID: "bitcoin", Currency: "usd", Direction: models.DirectionBelow,
+		TriggerMode: models.TriggerModeLevel, ThresholdPrice: 100}
+	m.expectFires(below, time.Minute, []float64{95}, []bool{true})
+}
+
+func TestMonitorPercentAlert(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := &models.Alert{ID: 9, CryptoID: "bitcoin", Currency: "usd", Type: models.AlertTypePercent,
+		Direction: models.DirectionCross, PercentChange: 10, ReferencePrice: 100}
+	alert.Observe(100, m.now)
+
+	// Scatta solo quando la variazione rispetto al riferimento supera il 10% in un verso o nell'altro
+	m.expectFires(alert, time.Minute, []float64{105, 95, 89}, []bool{false, false, true})
+}
+
+func TestMonitorRollingPercentFollowsChange24h(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := &models.Alert{ID: 10, CryptoID: "bitcoin", Currency: "usd", Type: models.AlertTypePercent,
+		Baseline: models.BaselineRolling, Direction: models.DirectionAbove, PercentChange: 10,
+		ReferencePrice: 100}
+	alert.Observe(100, m.now)
+
+	// +25% nelle 24 ore: il riferimento diventa 100 e 125 supera la soglia del 10%
+	m.prices.Set("bitcoin", "usd", 125).SetChange24h("bitcoin", "usd", 25)
+	snapshot, _ := m.monitor.fetchSnapshot(map[string][]string{"usd": {"bitcoin"}})
+	if _, fired := evaluateAlert(alert, snapshot["usd"]["bitcoin"], m.now.Add(time.Minute)); !fired {
+		t.Fatal("l'alert sulle 24 ore dovrebbe scattare")
+	}
+	if alert.ReferencePrice != 100 {
+		t.Fatalf("riferimento = %v, atteso 100", alert.ReferencePrice)
+	}
+}
+
+// observed crea un alert di prezzo su bitcoin sopra 100 con un'osservazione iniziale al prezzo indicato
+func (m *monitorTest) observed(alert models.Alert, price float64) *models.Alert {
+	alert.CryptoID, alert.Currency, alert.Type = "bitcoin", "usd", models.AlertTypePrice
+	alert.Direction, alert.ThresholdPrice = models.DirectionAbove, 100
+	alert.Observe(price, m.now)
+	return &alert
+}
+
+func hasEvent(events []models.AlertEvent, eventType string) bool {
+	for _, event := range events {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMonitorPnLCrossingFromZero(t *testing.T) {
+	m := newMonitorTest(t)
+
+	// Subito dopo un acquisto il P&L è 0%: è un'osservazione valida da cui rilevare l'attraversamento
+	alert := &models.Alert{CryptoID: "bitcoin", Currency: "usd", Type: models.AlertTypePositionPnL,
+		Direction: models.DirectionAbove, ThresholdPrice: 10}
+	alert.Observe(0, m.now)
+
+	if _, fired := evaluateAlert(alert, pricing.Quote{Price: 12}, m.now.Add(time.Minute)); !fired {
+		t.Fatal("l'alert sul P&L dovrebbe scattare passando da 0% a 12%")
+	}
+}
+
+func TestMonitorRecordsTriggerEvent(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := &models.Alert{ID: 11, CryptoID: "bitcoin", Currency: "usd", ThresholdPrice: 100}
+	alert.Observe(90, m.now)
+
+	events, fired := m.tick(alert, time.Minute, 105)
+	if !fired || len(events) != 1 {
+		t.Fatalf("scattato = %v, eventi = %v; atteso un solo evento", fired, events)
+	}
+	event := events[0]
+	if event.AlertID != 11 || event.Type != models.AlertEventTrigger || event.Price != 105 ||
+		event.Currency != "usd" || !event.CreatedAt.Equal(m.now) {
+		t.Fatalf("evento inatteso: %+v", event)
+	}
+	if alert.FireCount != 1 {
+		t.Fatalf("scatti = %d, atteso 1", alert.FireCount)
+	}
+}
+
+func TestMonitorRecurringCooldown(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := m.observed(models.Alert{Recurring: true, CooldownMinutes: 10}, 90)
+
+	if _, fired := m.tick(alert, time.Minute, 105); !fired {
+		t.Fatal("primo scatto atteso")
+	}
+
+	// Durante il cooldown l'alert non viene riarmato
+	events, fired := m.tick(alert, 5*time.Minute, 90)
+	if fired || hasEvent(events, models.AlertEventReset) || !alert.Triggered {
+		t.Fatal("l'alert non dovrebbe essere riarmato durante il cooldown")
+	}
+	if _, fired := m.tick(alert, time.Minute, 105); fired {
+		t.Fatal("l'alert non dovrebbe scattare durante il cooldown")
+	}
+
+	// Dopo il cooldown viene riarmato, registrando la causa, e può scattare di nuovo
+	events, _ = m.tick(alert, 10*time.Minute, 90)
+	if !hasEvent(events, models.AlertEventReset) || events[0].Reason != models.ResetAutomatic || alert.Triggered {
+		t.Fatal("l'alert dovrebbe essere riarmato dopo il cooldown")
+	}
+	events, fired = m.tick(alert, time.Minute, 105)
+	if !fired || !hasEvent(events, models.AlertEventTrigger) || alert.FireCount != 2 {
+		t.Fatalf("secondo scatto atteso, scatti = %d", alert.FireCount)
+	}
+}
+
+func TestMonitorRecurringHysteresis(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := m.observed(models.Alert{Recurring: true, HysteresisPercent: 5}, 90)
+
+	// Il riarmo richiede che il prezzo torni almeno del 5% sotto la soglia (95)
+	m.expectFires(alert, time.Minute, []float64{101, 97, 101, 94, 101}, []bool{true, false, false, false, true})
+	if alert.FireCount != 2 {
+		t.Fatalf("scatti = %d, attesi 2", alert.FireCount)
+	}
+}
+
+func TestMonitorRecurringMaxFires(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := m.observed(models.Alert{Recurring: true, MaxFires: 2}, 90)
+
+	m.expectFires(alert, time.Minute, []float64{101, 90, 101, 90, 101}, []bool{true, false, true, false, false})
+	if !alert.Exhausted() || !alert.Triggered {
+		t.Fatalf("l'alert dovrebbe essere esaurito dopo %d scatti", alert.FireCount)
+	}
+}
+
+func TestMonitorActivationWindow(t *testing.T) {
+	m := newMonitorTest(t)
+	activeFrom := m.now.Add(time.Hour)
+	alert := m.observed(models.Alert{ActiveFrom: &activeFrom}, 90)
+
+	// L'osservazione alla creazione precede la finestra e non vale come attraversamento
+	if _, fired := m.tick(alert, 2*time.Hour, 105); fired {
+		t.Fatal("la prima osservazione nella finestra non dovrebbe far scattare l'alert")
+	}
+	m.expectFires(alert, time.Minute, []float64{95, 105}, []bool{false, true})
+}
+
+func TestMonitorExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+	alert := &models.Alert{ID: 7, ExpiresAt: &expiresAt}
+
+	if alert.IsExpiredAt(now) || !alert.IsExpiredAt(expiresAt) {
+		t.Fatal("scadenza valutata in modo errato")
+	}
+
+	event, notify := expireAlert(alert, expiresAt)
+	if !alert.Expired || event.Type != models.AlertEventExpiry || event.AlertID != 7 || !notify {
+		t.Fatalf("scadenza inattesa: expired %v, evento %+v, notifica %v", alert.Expired, event, notify)
+	}
+
+	// Un alert già scattato scade senza avvisare il proprietario
+	fired := &models.Alert{ExpiresAt: &expiresAt, Triggered: true, FireCount: 1}
+	if _, notify := expireAlert(fired, expiresAt); notify {
+		t.Fatal("un alert già scattato non dovrebbe generare la notifica di scadenza")
+	}
+}
+
+func TestMonitorNotifiesTriggeredAlerts(t *testing.T) {
+	m := newMonitorTest(t)
+	ch := make(chan *models.Alert, 1)
+	m.monitor.SetTelegramNotificationChannel(ch)
+
+	alert := &models.Alert{ID: 2, CryptoID: "bitcoin", Currency: "usd", TriggerMode: models.TriggerModeLevel, ThresholdPrice: 100}
+	if _, fired := m.tick(alert, time.Minute, 105); !fired {
+		t.Fatal("l'alert dovrebbe scattare")
+	}
+	m.monitor.sendTelegramNotification(alert)
+
+	select {
+	case notified := <-ch:
+		if notified.ID != 2 {
+			t.Fatalf("notificato l'alert %d, atteso 2", notified.ID)
+		}
+	default:
+		t.Fatal("nessuna notifica inviata")
+	}
+
+	// Con il buffer pieno la notifica viene scartata senza bloccare il monitor
+	m.monitor.sendTelegramNotification(alert)
+	m.monitor.sendTelegramNotification(alert)
+	if len(ch) != 1 {
+		t.Fatalf("notifiche in coda = %d, attesa 1", len(ch))
+	}
+}
+
+func TestMonitorSnapshot(t *testing.T) {
+	m := newMonitorTest(t)
+	m.prices.Set("bitcoin", "usd", 65000).Set("ethereum", "usd", 3200).Set("bitcoin", "eur", 60000)
+
+	// Le criptovalute vengono richieste insieme, una volta per valuta; quelle senza prezzo restano escluse
+	snapshot, ok := m.monitor.fetchSnapshot(map[string][]string{
+		"usd": {"bitcoin", "ethereum", "unknown"},
+		"eur": {"bitcoin"},
+	})
+	if !ok {
+		t.Fatal("istantanea dei prezzi non disponibile")
+	}
+	if len(snapshot["usd"]) != 2 || snapshot["usd"]["bitcoin"].Price != 65000 || snapshot["usd"]["ethereum"].Price != 3200 {
+		t.Fatalf("istantanea in usd inattesa: %v", snapshot["usd"])
+	}
+	if len(snapshot["eur"]) != 1 || snapshot["eur"]["bitcoin"].Price != 60000 {
+		t.Fatalf("istantanea in eur inattesa: %v", snapshot["eur"])
+	}
+	if n := m.prices.Calls(); n != 2 {
+		t.Fatalf("richieste alla sorgente = %d, attese 2", n)
+	}
+}
+
+func TestMonitorSnapshotErrors(t *testing.T) {
+	m := newMonitorTest(t)
+
+	// Un errore generico lascia vuota solo la valuta interessata
+	m.prices.Fail(errors.New("timeout"))
+	snapshot, ok := m.monitor.fetchSnapshot(map[string][]string{"usd": {"bitcoin"}})
+	if !ok || len(snapshot) != 0 {
+		t.Fatalf("istantanea = %v, ok = %v; attesa vuota e valida", snapshot, ok)
+	}
+
+	// Con il circuit breaker aperto il ciclo viene saltato
+	m.prices.Fail(fmt.Errorf("coingecko: %w", pricing.ErrCircuitOpen))
+	if _, ok := m.monitor.fetchSnapshot(map[string][]string{"usd": {"bitcoin"}}); ok {
+		t.Fatal("il ciclo andrebbe saltato con il circuit breaker aperto")
+	}
+}
+
+func TestMonitorManualRearmOfExhaustedAlert(t *testing.T) {
+	m := newMonitorTest(t)
+	alert := m.observed(models.Alert{Direction: models.DirectionAbove, ThresholdPrice: 100,
+		Recurring: true, MaxFires: 1}, 90)
+	m.expectFires(alert, time.Minute, []float64{101, 90}, []bool{true, false})
+
+	// Un alert esaurito non scatta più anche se lo stato triggered viene azzerato direttamente
+	alert.Triggered = false
+	m.expectFires(alert, time.Minute, []float64{101, 90}, []bool{false, false})
+
+	// Il riarmo manuale fa ripartire il conteggio degli scatti
+	alert.Triggered = true
+	alert.Rearm()
+	if alert.Triggered || alert.FireCount != 0 {
+		t.Fatalf("riarmo inatteso: triggered %v, scatti %d", alert.Triggered, alert.FireCount)
+	}
+	m.expectFires(alert, time.Minute, []float64{101}, []bool{true})
+}
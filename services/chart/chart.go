@@ -0,0 +1,332 @@
+package chart
+
+import (
+	"crypto-tracker/services/history"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ErrNoData indica che non ci sono prezzi da disegnare
+var ErrNoData = errors.New("nessun prezzo da disegnare")
+
+// Stili di grafico disponibili
+const (
+	StyleCandles = "candles" // candele OHLC
+	StyleLine    = "line"    // linea dei prezzi di chiusura
+)
+
+// Dimensioni dell'immagine e margini dell'area del grafico
+const (
+	width        = 900
+	height       = 500
+	marginLeft   = 16
+	marginRight  = 110
+	marginTop    = 48
+	marginBottom = 36
+	textScale    = 2
+)
+
+// Colori del grafico
+var (
+	colorBackground = color.RGBA{0x14, 0x18, 0x21, 0xff}
+	colorGrid       = color.RGBA{0x2a, 0x2f, 0x3a, 0xff}
+	colorText       = color.RGBA{0xc8, 0xcc, 0xd4, 0xff}
+	colorAxis       = color.RGBA{0x80, 0x86, 0x92, 0xff}
+	colorUp         = color.RGBA{0x26, 0xa6, 0x9a, 0xff}
+	colorDown       = color.RGBA{0xef, 0x53, 0x50, 0xff}
+	colorLine       = color.RGBA{0x42, 0xa5, 0xf5, 0xff}
+
+	// Colori delle soglie, in base alla direzione dell'alert
+	ColorAbove = color.RGBA{0x66, 0xbb, 0x6a, 0xff}
+	ColorBelow = color.RGBA{0xef, 0x53, 0x50, 0xff}
+	ColorCross = color.RGBA{0xff, 0xa7, 0x26, 0xff}
+)
+
+// Line è una linea orizzontale disegnata sul grafico (es. la soglia di un alert)
+type Line struct {
+	Price float64
+	Label string
+	Color color.RGBA
+}
+
+// Options descrive il grafico da disegnare
+type Options struct {
+	Title      string
+	Candles    []history.Candle
+	Style      string
+	Lines      []Line
+	Location   *time.Location // fuso orario delle etichette dell'asse x (default UTC)
+	TimeFormat string         // formato delle etichette dell'asse x (default "02/01")
+}
+
+// Render disegna il grafico e lo scrive in formato PNG
+func Render(w io.Writer, opts Options) error {
+	if len(opts.Candles) == 0 {
+		return ErrNoData
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = "02/01"
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillRect(img, 0, 0, width, height, colorBackground)
+
+	plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+	scale := newPriceScale(opts.Candles, opts.Lines)
+
+	drawText(img, marginLeft, (marginTop-glyphHeight*textScale)/2, opts.Title, colorText, textScale)
+	drawGrid(img, plot, scale)
+	drawTimeAxis(img, plot, opts)
+
+	if opts.Style == StyleLine {
+		drawPriceLine(img, plot, scale, opts.Candles)
+	} else {
+		drawCandles(img, plot, scale, opts.Candles)
+	}
+
+	for _, line := range opts.Lines {
+		drawThreshold(img, plot, scale, line)
+	}
+
+	// Etichetta dell'ultimo prezzo
+	last := opts.Candles[len(opts.Candles)-1].Close
+	drawLabel(img, plot.Max.X+6, scale.y(plot, scale.clamp(last)), formatPrice(last, scale.step), colorText, colorBackground)
+
+	return png.Encode(w, img)
+}
+
+// priceScale converte i prezzi in coordinate verticali
+type priceScale struct {
+	low, high float64
+	step      float64 // distanza tra le linee della griglia
+}
+
+// newPriceScale calcola l'intervallo di prezzi da mostrare: quello delle candele, esteso alle
+// soglie non troppo lontane, così che una soglia molto distante non schiacci il grafico
+func newPriceScale(candles []history.Candle, lines []Line) priceScale {
+	low, high := candles[0].Low, candles[0].High
+	for _, candle := range candles[1:] {
+		low = min(low, candle.Low)
+		high = max(high, candle.High)
+	}
+
+	span := high - low
+	if span == 0 {
+		span = math.Max(math.Abs(high)*0.01, 1e-8)
+	}
+	for _, line := range lines {
+		if line.Price >= low-span && line.Price <= high+span {
+			low = min(low, line.Price)
+			high = max(high, line.Price)
+		}
+	}
+
+	span = high - low
+	if span == 0 {
+		span = math.Max(math.Abs(high)*0.01, 1e-8)
+	}
+	padding := span * 0.05
+	s := priceScale{low: low - padding, high: high + padding}
+	s.step = niceStep((s.high - s.low) / 5)
+	return s
+}
+
+// y restituisce la coordinata verticale di un prezzo nell'area del grafico
+func (s priceScale) y(plot image.Rectangle, price float64) int {
+	ratio := (s.high - price) / (s.high - s.low)
+	return plot.Min.Y + int(math.Round(ratio*float64(plot.Dy())))
+}
+
+// clamp limita un prezzo all'intervallo visibile
+func (s priceScale) clamp(price float64) float64 {
+	return math.Max(s.low, math.Min(s.high, price))
+}
+
+// niceStep arrotonda un passo a 1, 2 o 5 per una potenza di dieci
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch normalized := raw / magnitude; {
+	case normalized <= 1:
+		return magnitude
+	case normalized <= 2:
+		return 2 * magnitude
+	case normalized <= 5:
+		return 5 * magnitude
+	default:
+		return 10 * magnitude
+	}
+}
+
+// formatPrice formatta un prezzo con i decimali adatti al passo della griglia
+func formatPrice(price, step float64) string {
+	decimals := 0
+	if step > 0 && step < 1 {
+		decimals = min(8, int(math.Ceil(-math.Log10(step))))
+	}
+	return strconv.FormatFloat(price, 'f', decimals, 64)
+}
+
+// drawGrid disegna le linee orizzontali della griglia con le etichette dei prezzi
+func drawGrid(img *image.RGBA, plot image.Rectangle, scale priceScale) {
+	for price := math.Ceil(scale.low/scale.step) * scale.step; price <= scale.high; price += scale.step {
+		y := scale.y(plot, price)
+		hline(img, plot.Min.X, plot.Max.X, y, colorGrid)
+		drawText(img, plot.Max.X+8, y-glyphHeight*textScale/2, formatPrice(price, scale.step), colorAxis, textScale)
+	}
+	strokeRect(img, plot, colorGrid)
+}
+
+// drawTimeAxis disegna le etichette temporali sotto il grafico
+func drawTimeAxis(img *image.RGBA, plot image.Rectangle, opts Options) {
+	n := len(opts.Candles)
+	every := max(1, n/6)
+	for i := 0; i < n; i += every {
+		x := candleX(plot, n, i)
+		label := opts.Candles[i].Timestamp.In(opts.Location).Format(opts.TimeFormat)
+		vline(img, x, plot.Max.Y, plot.Max.Y+4, colorGrid)
+
+		// Mantieni l'etichetta entro i bordi dell'immagine
+		labelX := max(2, min(x-textWidth(label, textScale)/2, width-textWidth(label, textScale)-2))
+		drawText(img, labelX, plot.Max.Y+10, label, colorAxis, textScale)
+	}
+}
+
+// candleX restituisce la coordinata orizzontale del centro della candela i di n
+func candleX(plot image.Rectangle, n, i int) int {
+	slot := float64(plot.Dx()) / float64(n)
+	return plot.Min.X + int(slot*(float64(i)+0.5))
+}
+
+// drawCandles disegna le candele OHLC
+func drawCandles(img *image.RGBA, plot image.Rectangle, scale priceScale, candles []history.Candle) {
+	bodyWidth := max(1, int(float64(plot.Dx())/float64(len(candles))*0.6))
+
+	for i, candle := range candles {
+		c := colorUp
+		if candle.Close < candle.Open {
+			c = colorDown
+		}
+
+		x := candleX(plot, len(candles), i)
+		vline(img, x, scale.y(plot, candle.High), scale.y(plot, candle.Low), c)
+
+		top := scale.y(plot, math.Max(candle.Open, candle.Close))
+		bottom := scale.y(plot, math.Min(candle.Open, candle.Close))
+		fillRect(img, x-bodyWidth/2, top, bodyWidth, max(1, bottom-top), c)
+	}
+}
+
+// drawPriceLine disegna la linea dei prezzi di chiusura
+func drawPriceLine(img *image.RGBA, plot image.Rectangle, scale priceScale, candles []history.Candle) {
+	prevX, prevY := candleX(plot, len(candles), 0), scale.y(plot, candles[0].Close)
+	for i, candle := range candles {
+		x, y := candleX(plot, len(candles), i), scale.y(plot, candle.Close)
+		drawSegment(img, prevX, prevY, x, y, colorLine)
+		prevX, prevY = x, y
+	}
+}
+
+// drawThreshold disegna una soglia come linea tratteggiata con l'etichetta del prezzo;
+// le soglie fuori dall'intervallo visibile vengono disegnate sul bordo del grafico
+func drawThreshold(img *image.RGBA, plot image.Rectangle, scale priceScale, line Line) {
+	y := scale.y(plot, scale.clamp(line.Price))
+	for x := plot.Min.X; x < plot.Max.X; x += 10 {
+		hline(img, x, min(x+6, plot.Max.X), y, line.Color)
+	}
+
+	label := line.Label
+	if label == "" {
+		label = formatPrice(line.Price, scale.step)
+	}
+	switch {
+	case line.Price > scale.high:
+		label = ">" + label
+	case line.Price < scale.low:
+		label = "<" + label
+	}
+	drawLabel(img, plot.Max.X+6, y, label, colorBackground, line.Color)
+}
+
+// drawLabel disegna un'etichetta con sfondo colorato centrata verticalmente su y
+func drawLabel(img *image.RGBA, x, y int, text string, fg, bg color.Color) {
+	h := glyphHeight*textScale + 6
+	fillRect(img, x, y-h/2, textWidth(text, textScale)+6, h, bg)
+	drawText(img, x+3, y-h/2+3, text, fg, textScale)
+}
+
+// fillRect riempie un rettangolo
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	bounds := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			img.Set(px, py, c)
+		}
+	}
+}
+
+// strokeRect disegna il bordo di un rettangolo
+func strokeRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	hline(img, r.Min.X, r.Max.X, r.Min.Y, c)
+	hline(img, r.Min.X, r.Max.X, r.Max.Y, c)
+	vline(img, r.Min.X, r.Min.Y, r.Max.Y, c)
+	vline(img, r.Max.X, r.Min.Y, r.Max.Y, c)
+}
+
+// hline disegna una linea orizzontale tra x1 e x2
+func hline(img *image.RGBA, x1, x2, y int, c color.Color) {
+	fillRect(img, min(x1, x2), y, abs(x2-x1)+1, 1, c)
+}
+
+// vline disegna una linea verticale tra y1 e y2
+func vline(img *image.RGBA, x, y1, y2 int, c color.Color) {
+	fillRect(img, x, min(y1, y2), 1, abs(y2-y1)+1, c)
+}
+
+// drawSegment disegna un segmento spesso 2 pixel (algoritmo di Bresenham)
+func drawSegment(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		fillRect(img, x0, y0, 2, 2, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// abs restituisce il valore assoluto di un intero
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package chats
+
+import (
+	"crypto-tracker/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultListLimit è il numero di chat restituite per pagina quando non indicato
+const DefaultListLimit = 50
+
+// MaxListLimit è il numero massimo di chat restituite per pagina
+const MaxListLimit = 500
+
+// Seen registra un update ricevuto da una chat: la crea se è nuova, altrimenti ne aggiorna i dati
+// Telegram e l'ultimo accesso. Con reactivate (un messaggio ricevuto dalla chat, o il bot di nuovo
+// aggiunto) la chat torna attiva; gli altri update (es. la pressione di un pulsante su un vecchio
+// messaggio) non ne cambiano lo stato. Lingua e fuso orario di una chat esistente non vengono modificati.
+func Seen(db *gorm.DB, chat models.Chat, seenAt time.Time, reactivate bool) error {
+	chat.Status = models.ChatStatusActive
+	chat.FirstSeenAt, chat.LastSeenAt = seenAt, seenAt
+	chat.CreatedAt, chat.UpdatedAt = seenAt, seenAt
+
+	columns := []string{"type", "username", "first_name", "last_name", "title", "last_seen_at", "updated_at"}
+	if reactivate {
+		columns = append(columns, "status")
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(&chat).Error
+}
+
+// SetStatus aggiorna lo stato di una chat (es. bloccata quando l'utente ha bloccato il bot)
+func SetStatus(db *gorm.DB, chatID int64, status string) error {
+	return db.Model(&models.Chat{}).Where("id = ?", chatID).
+		Updates(map[string]any{"status": status, "updated_at": time.Now().UTC()}).Error
+}
+
+// Filter contiene i criteri di ricerca delle chat
+type Filter struct {
+	Status string // stato delle chat, vuoto per tutte
+	Limit  int
+	Offset int
+}
+
+// List restituisce le chat che rispettano il filtro, dalle più attive di recente,
+// insieme al numero totale di chat corrispondenti
+func List(db *gorm.DB, filter Filter) ([]models.Chat, int64, error) {
+	query := db.Model(&models.Chat{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > MaxListLimit {
+		limit = DefaultListLimit
+	}
+
+	var chats []models.Chat
+	err := query.Order("last_seen_at DESC, id").Limit(limit).Offset(filter.Offset).Find(&chats).Error
+	return chats, total, err
+}
+
+// Find restituisce le chat indicate registrate nel database, indicizzate per ID
+func Find(db *gorm.DB, chatIDs []int64) (map[int64]models.Chat, error) {
+	result := make(map[int64]models.Chat, len(chatIDs))
+	if len(chatIDs) == 0 {
+		return result, nil
+	}
+
+	var chats []models.Chat
+	if err := db.Where("id IN ?", chatIDs).Find(&chats).Error; err != nil {
+		return nil, err
+	}
+	for _, chat := range chats {
+		result[chat.ID] = chat
+	}
+	return result, nil
+}
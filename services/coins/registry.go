@@ -0,0 +1,261 @@
+package coins
+
+import (
+	"cmp"
+	"context"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultRefreshInterval è l'intervallo predefinito di aggiornamento del registro
+const DefaultRefreshInterval = 24 * time.Hour
+
+// refreshTimeout limita la durata di un aggiornamento (più richieste soggette al rate limit)
+const refreshTimeout = 5 * time.Minute
+
+// saveBatchSize è il numero di criptovalute salvate per ogni istruzione di inserimento
+const saveBatchSize = 1000
+
+// Registry è il registro locale delle criptovalute: risolve ID, simboli e nomi negli ID CoinGecko.
+// I dati sono salvati nel database e aggiornati periodicamente dalla sorgente dei prezzi;
+// un registro nil o vuoto lascia invariati gli ID (convertiti in minuscolo).
+type Registry struct {
+	db       *gorm.DB
+	prices   pricing.PriceProvider
+	interval time.Duration
+	stopChan chan struct{}
+
+	mu       sync.RWMutex
+	byID     map[string]models.Coin
+	bySymbol map[string][]models.Coin // ordinate per capitalizzazione decrescente
+	byName   map[string][]models.Coin // ordinate per capitalizzazione decrescente
+}
+
+// NewRegistry crea un registro delle criptovalute aggiornato ogni interval
+func NewRegistry(db *gorm.DB, prices pricing.PriceProvider, interval time.Duration) *Registry {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	return &Registry{
+		db:       db,
+		prices:   prices,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start carica il registro dal database e lo aggiorna in background: subito se è vuoto
+// o più vecchio dell'intervallo di aggiornamento, poi periodicamente
+func (r *Registry) Start() {
+	lastUpdate, err := r.Load()
+	if err != nil {
+		log.Printf("[Coins] Errore nel caricamento del registro: %v", err)
+	}
+
+	go func() {
+		if time.Since(lastUpdate) >= r.interval {
+			r.refreshWithTimeout()
+		}
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.refreshWithTimeout()
+			case <-r.stopChan:
+				log.Println("[Coins] Aggiornamento del registro terminato")
+				return
+			}
+		}
+	}()
+}
+
+// Stop interrompe l'aggiornamento periodico del registro
+func (r *Registry) Stop() {
+	close(r.stopChan)
+}
+
+// refreshWithTimeout aggiorna il registro registrando nei log l'eventuale errore
+func (r *Registry) refreshWithTimeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	if err := r.Refresh(ctx); err != nil {
+		if errors.Is(err, pricing.ErrCoinListUnsupported) {
+			log.Println("[Coins] La sorgente dei prezzi non fornisce l'elenco delle criptovalute, registro non aggiornato")
+			return
+		}
+		log.Printf("[Coins] Errore nell'aggiornamento del registro: %v", err)
+	}
+}
+
+// Load carica il registro dal database e restituisce l'istante dell'ultimo aggiornamento
+func (r *Registry) Load() (time.Time, error) {
+	var coins []models.Coin
+	if err := r.db.Find(&coins).Error; err != nil {
+		return time.Time{}, err
+	}
+
+	var lastUpdate time.Time
+	for _, coin := range coins {
+		if coin.UpdatedAt.After(lastUpdate) {
+			lastUpdate = coin.UpdatedAt
+		}
+	}
+
+	r.index(coins)
+	log.Printf("[Coins] Registro caricato dal database: %d criptovalute", len(coins))
+	return lastUpdate, nil
+}
+
+// Refresh scarica l'elenco delle criptovalute dalla sorgente, lo salva nel database
+// rimuovendo quelle non più elencate e aggiorna gli indici in memoria
+func (r *Registry) Refresh(ctx context.Context) error {
+	infos, err := pricing.ListCoins(ctx, r.prices)
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		return errors.New("elenco delle criptovalute vuoto")
+	}
+
+	now := time.Now().UTC()
+	coins := make([]models.Coin, 0, len(infos))
+	for _, info := range infos {
+		if info.ID == "" {
+			continue
+		}
+		coins = append(coins, models.Coin{
+			ID:            info.ID,
+			Symbol:        strings.ToLower(info.Symbol),
+			Name:          info.Name,
+			MarketCap:     info.MarketCap,
+			MarketCapRank: info.MarketCapRank,
+			UpdatedAt:     now,
+		})
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(&coins, saveBatchSize).Error; err != nil {
+			return err
+		}
+		return tx.Where("updated_at < ?", now).Delete(&models.Coin{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	r.index(coins)
+	log.Printf("[Coins] Registro aggiornato: %d criptovalute", len(coins))
+	return nil
+}
+
+// index ricostruisce gli indici in memoria per ID, simbolo e nome
+func (r *Registry) index(coins []models.Coin) {
+	byID := make(map[string]models.Coin, len(coins))
+	bySymbol := make(map[string][]models.Coin)
+	byName := make(map[string][]models.Coin)
+
+	for _, coin := range coins {
+		byID[coin.ID] = coin
+		bySymbol[coin.Symbol] = append(bySymbol[coin.Symbol], coin)
+		name := strings.ToLower(coin.Name)
+		byName[name] = append(byName[name], coin)
+	}
+	for _, group := range bySymbol {
+		slices.SortFunc(group, compareCoins)
+	}
+	for _, group := range byName {
+		slices.SortFunc(group, compareCoins)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID, r.bySymbol, r.byName = byID, bySymbol, byName
+}
+
+// compareCoins ordina le criptovalute per capitalizzazione decrescente; quelle senza
+// capitalizzazione nota vanno in fondo, a parità di condizioni vince l'ID più corto
+func compareCoins(a, b models.Coin) int {
+	if (a.MarketCapRank > 0) != (b.MarketCapRank > 0) {
+		if a.MarketCapRank > 0 {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Or(
+		cmp.Compare(b.MarketCap, a.MarketCap),
+		cmp.Compare(len(a.ID), len(b.ID)),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// Lookup cerca una criptovaluta per ID, simbolo o nome (senza distinzione tra maiuscole e minuscole).
+// In caso di simboli o nomi condivisi restituisce la criptovaluta con la capitalizzazione maggiore;
+// un ID esatto ha la precedenza, a meno che non sia una criptovaluta minore senza capitalizzazione
+// nota il cui ID coincide con il simbolo di una criptovaluta principale (es. "sol").
+func (r *Registry) Lookup(query string) (models.Coin, bool) {
+	if r == nil {
+		return models.Coin{}, false
+	}
+	key := strings.ToLower(strings.TrimSpace(query))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exact, hasExact := r.byID[key]
+	if hasExact && exact.MarketCapRank > 0 {
+		return exact, true
+	}
+
+	var best models.Coin
+	var hasBest bool
+	for _, group := range [][]models.Coin{r.bySymbol[key], r.byName[key]} {
+		if len(group) > 0 && (!hasBest || compareCoins(group[0], best) < 0) {
+			best, hasBest = group[0], true
+		}
+	}
+
+	switch {
+	case hasBest && (best.MarketCapRank > 0 || !hasExact):
+		return best, true
+	case hasExact:
+		return exact, true
+	default:
+		return models.Coin{}, false
+	}
+}
+
+// Resolve converte un ID, simbolo o nome nell'ID CoinGecko; se la criptovaluta non è nel
+// registro restituisce il valore in minuscolo, lasciando la verifica alla sorgente dei prezzi
+func (r *Registry) Resolve(query string) string {
+	if coin, ok := r.Lookup(query); ok {
+		return coin.ID
+	}
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+// Size restituisce il numero di criptovalute nel registro
+func (r *Registry) Size() int {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.byID)
+}
@@ -0,0 +1,69 @@
+package coins
+
+import (
+	"crypto-tracker/models"
+	"testing"
+)
+
+// newTestRegistry crea un registro in memoria con le criptovalute indicate
+func newTestRegistry(coins ...models.Coin) *Registry {
+	r := &Registry{}
+	r.index(coins)
+	return r
+}
+
+var testCoins = []models.Coin{
+	{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin", MarketCap: 1.2e12, MarketCapRank: 1},
+	{ID: "ethereum", Symbol: "eth", Name: "Ethereum", MarketCap: 4e11, MarketCapRank: 2},
+	{ID: "solana", Symbol: "sol", Name: "Solana", MarketCap: 8e10, MarketCapRank: 5},
+	{ID: "avalanche-2", Symbol: "avax", Name: "Avalanche", MarketCap: 1.5e10, MarketCapRank: 12},
+	{ID: "uniswap", Symbol: "uni", Name: "Uniswap", MarketCap: 6e9, MarketCapRank: 20},
+	// Criptovalute minori senza capitalizzazione nota che condividono ID, simboli o nomi
+	{ID: "sol", Symbol: "sol", Name: "Sol Token"},
+	{ID: "btc", Symbol: "btc", Name: "BTC Classic"},
+	{ID: "eth-token", Symbol: "eth", Name: "ETH Token"},
+	{ID: "uni-token", Symbol: "unt", Name: "Uni"},
+	{ID: "foo", Symbol: "foo", Name: "Foo"},
+	{ID: "foo-coin", Symbol: "foo", Name: "Foo Coin"},
+	{ID: "bar-two", Symbol: "bar", Name: "Bar Two"},
+	{ID: "bar-one", Symbol: "bar", Name: "Bar One"},
+	// Due criptovalute con capitalizzazione nota e lo stesso simbolo
+	{ID: "small-usdx", Symbol: "usdx", Name: "Small USDX", MarketCap: 1e6, MarketCapRank: 900},
+	{ID: "big-usdx", Symbol: "usdx", Name: "Big USDX", MarketCap: 5e7, MarketCapRank: 400},
+}
+
+func TestRegistryResolve(t *testing.T) {
+	r := newTestRegistry(testCoins...)
+
+	tests := []struct {
+		query, want string
+	}{
+		{"bitcoin", "bitcoin"},
+		{"BTC", "bitcoin"},           // il simbolo di una criptovaluta principale batte l'ID di una minore
+		{"sol", "solana"},            // idem per "sol"
+		{"eth", "ethereum"},          // simbolo condiviso: vince la capitalizzazione maggiore
+		{" Solana ", "solana"},       // spazi e maiuscole ignorati
+		{"avalanche", "avalanche-2"}, // nome diverso dall'ID
+		{"uni", "uniswap"},           // il simbolo di una criptovaluta principale batte il nome di una minore
+		{"usdx", "big-usdx"},         // a parità di simbolo vince la capitalizzazione maggiore
+		{"foo", "foo"},               // tra criptovalute minori l'ID esatto ha la precedenza
+		{"bar", "bar-one"},           // a parità di lunghezza dell'ID vince l'ordine alfabetico
+		{"foo coin", "foo-coin"},     // ricerca per nome
+		{"DogeX", "dogex"},           // non nel registro: invariato, in minuscolo
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := r.Resolve(tt.query); got != tt.want {
+				t.Errorf("Resolve(%q) = %q, atteso %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilRegistryResolve(t *testing.T) {
+	var r *Registry
+	if got := r.Resolve(" BTC "); got != "btc" {
+		t.Errorf("Resolve = %q, atteso %q", got, "btc")
+	}
+}
@@ -0,0 +1,181 @@
+package coins
+
+import (
+	"cmp"
+	"crypto-tracker/models"
+	"slices"
+	"strings"
+)
+
+// DefaultSearchLimit è il numero predefinito di risultati di una ricerca
+const DefaultSearchLimit = 10
+
+// MaxSearchLimit è il numero massimo di risultati di una ricerca
+const MaxSearchLimit = 50
+
+// minFuzzyLength è la lunghezza minima della ricerca per le corrispondenze parziali e con errori di battitura
+const minFuzzyLength = 3
+
+// Punteggi delle corrispondenze, dalla più forte alla più debole
+const (
+	scoreExact    = 100 // ID, simbolo o nome identici alla ricerca
+	scorePrefix   = 80  // ID, simbolo o nome che iniziano con la ricerca
+	scoreWord     = 70  // una parola del nome inizia con la ricerca (es. "inu" per "Shiba Inu")
+	scoreContains = 60  // ID o nome che contengono la ricerca
+	scoreTypo     = 50  // ID, simbolo o nome simili alla ricerca, meno 10 punti per ogni modifica necessaria
+)
+
+// Match è un risultato della ricerca nel registro
+type Match struct {
+	ID            string `json:"id"`
+	Symbol        string `json:"symbol"`
+	Name          string `json:"name"`
+	MarketCapRank int    `json:"market_cap_rank,omitempty"`
+	Score         int    `json:"score"` // Punteggio della corrispondenza (100 = identica)
+}
+
+// Search cerca nel registro le criptovalute che corrispondono al testo indicato per ID, simbolo
+// o nome, anche parzialmente o con errori di battitura. I risultati sono ordinati per punteggio
+// e, a parità di punteggio, per capitalizzazione decrescente.
+func (r *Registry) Search(query string, limit int) []Match {
+	if r == nil {
+		return nil
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	type candidate struct {
+		coin  models.Coin
+		score int
+	}
+
+	r.mu.RLock()
+	var candidates []candidate
+	for _, coin := range r.byID {
+		if score := matchScore(query, coin); score > 0 {
+			candidates = append(candidates, candidate{coin: coin, score: score})
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), compareCoins(a.coin, b.coin))
+	})
+
+	matches := make([]Match, 0, min(limit, len(candidates)))
+	for _, c := range candidates[:min(limit, len(candidates))] {
+		matches = append(matches, Match{
+			ID:            c.coin.ID,
+			Symbol:        c.coin.Symbol,
+			Name:          c.coin.Name,
+			MarketCapRank: c.coin.MarketCapRank,
+			Score:         c.score,
+		})
+	}
+	return matches
+}
+
+// matchScore calcola il punteggio di una criptovaluta per la ricerca (già in minuscolo); 0 se non corrisponde
+func matchScore(query string, coin models.Coin) int {
+	name := strings.ToLower(coin.Name)
+	keys := []string{coin.ID, coin.Symbol, name}
+
+	if slices.Contains(keys, query) {
+		return scoreExact
+	}
+	for _, key := range keys {
+		if strings.HasPrefix(key, query) {
+			return scorePrefix
+		}
+	}
+
+	// Con poche lettere le corrispondenze parziali sarebbero troppe e poco significative
+	if len([]rune(query)) < minFuzzyLength {
+		return 0
+	}
+
+	if words := strings.Fields(name); len(words) > 1 {
+		for _, word := range words[1:] {
+			if strings.HasPrefix(word, query) {
+				return scoreWord
+			}
+		}
+	}
+	if strings.Contains(coin.ID, query) || strings.Contains(name, query) {
+		return scoreContains
+	}
+
+	maxEdits := maxTypos(query)
+	best := maxEdits + 1
+	for _, key := range keys {
+		best = min(best, editDistance(query, key, maxEdits))
+	}
+	if best <= maxEdits {
+		return scoreTypo - 10*best
+	}
+	return 0
+}
+
+// maxTypos restituisce il numero di errori di battitura tollerati in base alla lunghezza della ricerca
+func maxTypos(query string) int {
+	switch n := len([]rune(query)); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// editDistance calcola la distanza tra due stringhe contando inserimenti, cancellazioni,
+// sostituzioni e scambi di lettere adiacenti; oltre limit restituisce limit+1
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	// Tre righe della matrice: due precedenti (per gli scambi) e quella corrente
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return min(prev[len(rb)], limit+1)
+}
+
+// abs restituisce il valore assoluto di un intero
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
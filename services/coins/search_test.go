@@ -0,0 +1,66 @@
+package coins
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"bitcoin", "bitcoin", 2, 0},
+		{"bitcon", "bitcoin", 2, 1},    // inserimento
+		{"bitcoins", "bitcoin", 2, 1},  // cancellazione
+		{"bitcoim", "bitcoin", 2, 1},   // sostituzione
+		{"bticoin", "bitcoin", 2, 1},   // scambio di lettere adiacenti
+		{"etherum", "ethereum", 2, 1},  // lettera mancante
+		{"etehreum", "ethereum", 2, 1}, // scambio
+		{"solanaxx", "solana", 2, 2},   // due modifiche
+		{"cardano", "bitcoin", 3, 4},   // oltre il limite: limit+1
+		{"btc", "bitcoin", 2, 3},       // lunghezze troppo diverse
+		{"", "eth", 3, 3},
+		{"èth", "eth", 1, 1}, // le lettere accentate contano come una sola modifica
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"→"+tt.b, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+				t.Errorf("editDistance(%q, %q, %d) = %d, atteso %d", tt.a, tt.b, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistrySearch(t *testing.T) {
+	r := newTestRegistry(testCoins...)
+
+	tests := []struct {
+		query     string
+		wantFirst string
+		wantScore int
+	}{
+		{"btc", "bitcoin", scoreExact},
+		{"bitc", "bitcoin", scorePrefix},
+		{"usdx", "big-usdx", scoreExact},
+		{"coin", "foo-coin", scoreWord},
+		{"wap", "uniswap", scoreContains},
+		{"etherium", "ethereum", scoreTypo - 10},
+		{"slana", "solana", scoreTypo - 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			matches := r.Search(tt.query, 5)
+			if len(matches) == 0 {
+				t.Fatalf("nessun risultato per %q", tt.query)
+			}
+			if matches[0].ID != tt.wantFirst || matches[0].Score != tt.wantScore {
+				t.Errorf("primo risultato = %s (%d), atteso %s (%d)", matches[0].ID, matches[0].Score, tt.wantFirst, tt.wantScore)
+			}
+		})
+	}
+
+	if matches := r.Search("xy", 5); len(matches) != 0 {
+		t.Errorf("risultati inattesi per una ricerca breve senza corrispondenze: %+v", matches)
+	}
+}
@@ -0,0 +1,87 @@
+package digest
+
+import (
+	"context"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/watchlist"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FiredAlert è uno scatto di alert avvenuto dopo l'ultimo digest
+type FiredAlert struct {
+	Alert models.Alert
+	Event models.AlertEvent
+}
+
+// Digest è il riepilogo periodico inviato a una chat
+type Digest struct {
+	Schedule     models.DigestSchedule
+	Since        time.Time         // inizio del periodo riepilogato (ultimo digest o iscrizione)
+	GeneratedAt  time.Time         // istante di generazione
+	Watchlist    []watchlist.Entry // prezzi e variazioni nelle 24 ore della watchlist
+	ActiveAlerts []models.Alert    // alert in attesa di scattare
+	Fired        []FiredAlert      // alert scattati nel periodo, dal più recente
+	Sent         chan error        // se presente, riceve l'esito dell'invio (nil se consegnato)
+}
+
+// Build raccoglie i dati del digest di una chat: la watchlist viene quotata con un'unica richiesta di prezzi
+func Build(ctx context.Context, db *gorm.DB, prices pricing.PriceProvider, schedule models.DigestSchedule, now time.Time) (*Digest, error) {
+	since := schedule.CreatedAt
+	if schedule.LastRunAt != nil {
+		since = *schedule.LastRunAt
+	}
+
+	digest := &Digest{Schedule: schedule, Since: since, GeneratedAt: now}
+
+	items, err := watchlist.Items(db, schedule.UserChatID)
+	if err != nil {
+		return nil, err
+	}
+	if digest.Watchlist, err = watchlist.Snapshot(ctx, prices, watchlist.CoinIDs(items), schedule.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := db.Where("user_chat_id = ? AND triggered = ? AND expired = ?", schedule.UserChatID, false, false).
+		Order("id").Find(&digest.ActiveAlerts).Error; err != nil {
+		return nil, err
+	}
+
+	var events []models.AlertEvent
+	if err := db.Where("type = ? AND created_at > ? AND created_at <= ?", models.AlertEventTrigger, since, now).
+		Where("alert_id IN (?)", db.Model(&models.Alert{}).Select("id").Where("user_chat_id = ?", schedule.UserChatID)).
+		Order("created_at DESC, id DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	if len(events) > 0 {
+		alertIDs := make([]uint, 0, len(events))
+		for _, event := range events {
+			alertIDs = append(alertIDs, event.AlertID)
+		}
+
+		var alerts []models.Alert
+		if err := db.Where("id IN ?", alertIDs).Find(&alerts).Error; err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]models.Alert, len(alerts))
+		for _, alert := range alerts {
+			byID[alert.ID] = alert
+		}
+
+		for _, event := range events {
+			if alert, ok := byID[event.AlertID]; ok {
+				digest.Fired = append(digest.Fired, FiredAlert{Alert: alert, Event: event})
+			}
+		}
+	}
+
+	return digest, nil
+}
+
+// Empty indica se il digest non contiene nulla da riportare
+func (d *Digest) Empty() bool {
+	return len(d.Watchlist) == 0 && len(d.ActiveAlerts) == 0 && len(d.Fired) == 0
+}
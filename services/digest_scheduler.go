@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"crypto-tracker/models"
+	"crypto-tracker/services/chats"
+	"crypto-tracker/services/digest"
+	"crypto-tracker/services/pricing"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// digestSendTimeout è l'attesa massima dell'esito dell'invio di un digest da parte del bot
+const digestSendTimeout = time.Minute
+
+// DigestScheduler invia i digest periodici alle chat iscritte. Le pianificazioni sono
+// salvate nel database: dopo un riavvio i digest arretrati vengono inviati al primo controllo.
+type DigestScheduler struct {
+	db       *gorm.DB
+	prices   pricing.PriceProvider
+	interval time.Duration
+	stopChan chan struct{}
+	digestCh chan *digest.Digest // Canale per l'invio dei digest tramite Telegram
+}
+
+// NewDigestScheduler crea una nuova istanza dello scheduler dei digest
+func NewDigestScheduler(db *gorm.DB, prices pricing.PriceProvider, interval time.Duration) *DigestScheduler {
+	if interval < time.Second {
+		interval = time.Minute // Valore di default
+	}
+
+	return &DigestScheduler{
+		db:       db,
+		prices:   prices,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// SetTelegramDigestChannel imposta il canale per inviare i digest tramite Telegram
+func (ds *DigestScheduler) SetTelegramDigestChannel(ch chan *digest.Digest) {
+	ds.digestCh = ch
+}
+
+// Start avvia lo scheduler in background
+func (ds *DigestScheduler) Start() {
+	log.Printf("[DigestScheduler] Avvio dello scheduler (intervallo: %v)", ds.interval)
+
+	go func() {
+		ticker := time.NewTicker(ds.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ds.runDue(time.Now().UTC())
+			case <-ds.stopChan:
+				log.Println("[DigestScheduler] Scheduler terminato")
+				return
+			}
+		}
+	}()
+}
+
+// Stop interrompe lo scheduler in background
+func (ds *DigestScheduler) Stop() {
+	log.Println("[DigestScheduler] Arresto dello scheduler...")
+	close(ds.stopChan)
+}
+
+// runDue invia i digest il cui invio è previsto entro now e pianifica il successivo
+func (ds *DigestScheduler) runDue(now time.Time) {
+	// Senza un canale di invio (bot non avviato) i digest restano in attesa
+	if ds.digestCh == nil {
+		return
+	}
+
+	var schedules []models.DigestSchedule
+	if err := ds.db.Where("next_run_at <= ?", now).Order("next_run_at").Find(&schedules).Error; err != nil {
+		log.Printf("[DigestScheduler] Errore nel recupero dei digest da inviare: %v", err)
+		return
+	}
+
+	chatIDs := make([]int64, 0, len(schedules))
+	for _, schedule := range schedules {
+		chatIDs = append(chatIDs, schedule.UserChatID)
+	}
+	// Lo stato e il fuso orario del digest sono quelli della chat (predefiniti se non registrata)
+	chatsByID, err := chats.Find(ds.db, chatIDs)
+	if err != nil {
+		log.Printf("[DigestScheduler] Errore nel recupero delle chat dei digest: %v", err)
+		return
+	}
+
+	for i := range schedules {
+		schedule := &schedules[i]
+		chat := chatsByID[schedule.UserChatID]
+		loc := chat.Location()
+
+		// Una chat che ha bloccato il bot non riceverebbe il digest: si passa al prossimo invio
+		if chat.Status == models.ChatStatusBlocked {
+			schedule.NextRunAt = schedule.NextRun(now, loc)
+			schedule.UpdatedAt = now
+			if err := ds.db.Save(schedule).Error; err != nil {
+				log.Printf("[DigestScheduler] Errore nell'aggiornamento del digest per la chat %d: %v", schedule.UserChatID, err)
+			}
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+		result, err := digest.Build(ctx, ds.db, ds.prices, *schedule, now)
+		cancel()
+		if err != nil {
+			// Il digest resta in scadenza e viene ritentato al prossimo controllo
+			log.Printf("[DigestScheduler] Errore nella preparazione del digest per la chat %d: %v", schedule.UserChatID, err)
+			continue
+		}
+
+		// Il prossimo invio viene pianificato solo dopo la consegna: un digest non consegnato
+		// resta in scadenza e viene ritentato al prossimo controllo
+		log.Printf("[DigestScheduler] Invio del digest alla chat %d", schedule.UserChatID)
+		if err := ds.deliver(result); err != nil {
+			log.Printf("[DigestScheduler] Digest per la chat %d non consegnato: %v", schedule.UserChatID, err)
+			continue
+		}
+
+		// Un digest arretrato da più esecuzioni viene inviato una sola volta
+		schedule.LastRunAt = &now
+		schedule.NextRunAt = schedule.NextRun(now, loc)
+		schedule.UpdatedAt = now
+		if err := ds.db.Save(schedule).Error; err != nil {
+			log.Printf("[DigestScheduler] Errore nell'aggiornamento del digest per la chat %d: %v", schedule.UserChatID, err)
+			continue
+		}
+		log.Printf("[DigestScheduler] Digest consegnato alla chat %d (prossimo: %s)", schedule.UserChatID, schedule.NextRunAt.Format(time.RFC3339))
+	}
+}
+
+// deliver passa un digest al bot e ne attende l'esito dell'invio
+func (ds *DigestScheduler) deliver(result *digest.Digest) error {
+	result.Sent = make(chan error, 1)
+
+	select {
+	case ds.digestCh <- result:
+	default:
+		return errors.New("canale dei digest pieno")
+	}
+
+	timer := time.NewTimer(digestSendTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result.Sent:
+		return err
+	case <-timer.C:
+		return errors.New("nessun esito dell'invio entro il tempo massimo")
+	case <-ds.stopChan:
+		return errors.New("scheduler arrestato")
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"crypto-tracker/services/digest"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDigestSchedulerDeliver(t *testing.T) {
+	sendErr := errors.New("invio non riuscito")
+
+	tests := []struct {
+		name    string
+		result  error
+		wantErr bool
+	}{
+		{name: "consegnato", result: nil},
+		{name: "invio fallito", result: sendErr, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := NewDigestScheduler(nil, nil, time.Minute)
+			ch := make(chan *digest.Digest, 1)
+			ds.SetTelegramDigestChannel(ch)
+
+			go func() {
+				result := <-ch
+				result.Sent <- tt.result
+			}()
+
+			err := ds.deliver(&digest.Digest{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("errore = %v, atteso errore: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDigestSchedulerDeliverFullChannel(t *testing.T) {
+	ds := NewDigestScheduler(nil, nil, time.Minute)
+	ds.SetTelegramDigestChannel(make(chan *digest.Digest))
+
+	if err := ds.deliver(&digest.Digest{}); err == nil {
+		t.Fatal("errore atteso con il canale dei digest pieno")
+	}
+}
@@ -0,0 +1,134 @@
+package history
+
+import (
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point è un prezzo della serie storica
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+}
+
+// Candle riassume i prezzi di un intervallo (OHLC)
+type Candle struct {
+	Timestamp time.Time `json:"timestamp"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+}
+
+// Summary riassume l'andamento dei prezzi in un periodo
+type Summary struct {
+	From          time.Time
+	To            time.Time
+	Open          float64
+	High          float64
+	Low           float64
+	Close         float64
+	ChangePercent float64
+	Samples       int
+}
+
+// Points converte i campioni in punti, usando il prezzo di chiusura dei campioni aggregati
+func Points(samples []models.PriceSample) []Point {
+	points := make([]Point, 0, len(samples))
+	for _, sample := range samples {
+		points = append(points, Point{Timestamp: sample.SampledAt, Price: sample.Close})
+	}
+	return points
+}
+
+// SamplesFromPoints converte i prezzi storici ottenuti da una sorgente in campioni
+func SamplesFromPoints(coinID, currency string, points []pricing.PricePoint) []models.PriceSample {
+	samples := make([]models.PriceSample, 0, len(points))
+	for _, point := range points {
+		samples = append(samples, models.PriceSample{
+			CoinID:    coinID,
+			Currency:  currency,
+			SampledAt: point.Time,
+			Open:      point.Price,
+			High:      point.Price,
+			Low:       point.Price,
+			Close:     point.Price,
+		})
+	}
+	return samples
+}
+
+// Candles raggruppa i campioni (in ordine cronologico) in intervalli di durata interval
+func Candles(samples []models.PriceSample, interval time.Duration) []Candle {
+	var candles []Candle
+	for _, sample := range samples {
+		start := sample.SampledAt.Truncate(interval)
+
+		if n := len(candles); n > 0 && candles[n-1].Timestamp.Equal(start) {
+			last := &candles[n-1]
+			last.High = max(last.High, sample.High)
+			last.Low = min(last.Low, sample.Low)
+			last.Close = sample.Close
+			continue
+		}
+
+		candles = append(candles, Candle{
+			Timestamp: start,
+			Open:      sample.Open,
+			High:      sample.High,
+			Low:       sample.Low,
+			Close:     sample.Close,
+		})
+	}
+	return candles
+}
+
+// Summarize riassume i campioni (in ordine cronologico); restituisce false se non ce ne sono
+func Summarize(samples []models.PriceSample) (Summary, bool) {
+	if len(samples) == 0 {
+		return Summary{}, false
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	summary := Summary{
+		From:    first.SampledAt,
+		To:      last.SampledAt,
+		Open:    first.Open,
+		High:    first.High,
+		Low:     first.Low,
+		Close:   last.Close,
+		Samples: len(samples),
+	}
+	for _, sample := range samples[1:] {
+		summary.High = max(summary.High, sample.High)
+		summary.Low = min(summary.Low, sample.Low)
+	}
+	if summary.Open != 0 {
+		summary.ChangePercent = (summary.Close - summary.Open) / summary.Open * 100
+	}
+
+	return summary, true
+}
+
+// ParseInterval interpreta la durata di un intervallo come "15m", "1h" o "1d"
+func ParseInterval(value string) (time.Duration, error) {
+	invalid := i18n.Errorf("validation.invalid_interval", value)
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, invalid
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < time.Minute {
+		return 0, invalid
+	}
+	return d, nil
+}
@@ -0,0 +1,157 @@
+package history
+
+import (
+	"crypto-tracker/models"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+
+// sample crea un campione all'istante start+offset con i prezzi indicati
+func sample(offset time.Duration, open, high, low, close float64) models.PriceSample {
+	return models.PriceSample{
+		CoinID:    "bitcoin",
+		Currency:  "usd",
+		SampledAt: start.Add(offset),
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+	}
+}
+
+func TestCandles(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []models.PriceSample
+		interval time.Duration
+		want     []Candle
+	}{
+		{
+			name:     "nessun campione",
+			interval: time.Hour,
+		},
+		{
+			name: "un intervallo",
+			samples: []models.PriceSample{
+				sample(0, 100, 100, 100, 100),
+				sample(10*time.Minute, 105, 110, 104, 108),
+				sample(20*time.Minute, 108, 108, 95, 97),
+			},
+			interval: time.Hour,
+			want: []Candle{
+				{Timestamp: start, Open: 100, High: 110, Low: 95, Close: 97},
+			},
+		},
+		{
+			name: "più intervalli con un buco",
+			samples: []models.PriceSample{
+				sample(5*time.Minute, 100, 100, 100, 100),
+				sample(20*time.Minute, 102, 102, 102, 102),
+				sample(50*time.Minute, 90, 90, 90, 90),
+			},
+			interval: 15 * time.Minute,
+			want: []Candle{
+				{Timestamp: start, Open: 100, High: 100, Low: 100, Close: 100},
+				{Timestamp: start.Add(15 * time.Minute), Open: 102, High: 102, Low: 102, Close: 102},
+				{Timestamp: start.Add(45 * time.Minute), Open: 90, High: 90, Low: 90, Close: 90},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Candles(tt.samples, tt.interval)
+			if len(got) != len(tt.want) {
+				t.Fatalf("candele = %+v, attese %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("candela %d = %+v, attesa %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []models.PriceSample
+		want    Summary
+		wantOK  bool
+	}{
+		{
+			name: "nessun campione",
+		},
+		{
+			name:    "un campione",
+			samples: []models.PriceSample{sample(0, 100, 100, 100, 100)},
+			want:    Summary{From: start, To: start, Open: 100, High: 100, Low: 100, Close: 100, Samples: 1},
+			wantOK:  true,
+		},
+		{
+			name: "rialzo con minimo intermedio",
+			samples: []models.PriceSample{
+				sample(0, 100, 105, 98, 104),
+				sample(time.Hour, 104, 106, 90, 95),
+				sample(2*time.Hour, 95, 130, 95, 125),
+			},
+			want: Summary{
+				From: start, To: start.Add(2 * time.Hour),
+				Open: 100, High: 130, Low: 90, Close: 125,
+				ChangePercent: 25, Samples: 3,
+			},
+			wantOK: true,
+		},
+		{
+			name:    "apertura nulla",
+			samples: []models.PriceSample{sample(0, 0, 10, 0, 10)},
+			want:    Summary{From: start, To: start, High: 10, Close: 10, Samples: 1},
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Summarize(tt.samples)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("Summarize = %+v, %v; atteso %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "15m", want: 15 * time.Minute},
+		{value: "1h", want: time.Hour},
+		{value: "4h", want: 4 * time.Hour},
+		{value: "1d", want: 24 * time.Hour},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "1m", want: time.Minute},
+		{value: "30s", wantErr: true},
+		{value: "0d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "d", wantErr: true},
+		{value: "1w", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseInterval(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInterval(%q) errore = %v, atteso errore: %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseInterval(%q) = %v, atteso %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package history
+
+import (
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultRetention è per quanto tempo vengono conservati i campioni di prezzo
+	DefaultRetention = 90 * 24 * time.Hour
+
+	// DefaultRawRetention è per quanto tempo vengono conservate le singole osservazioni
+	// prima di essere aggregate in campioni orari
+	DefaultRawRetention = 7 * 24 * time.Hour
+
+	// DownsampleResolution è la durata dei campioni aggregati
+	DownsampleResolution = time.Hour
+
+	// compactionInterval è ogni quanto vengono applicate aggregazione e retention
+	compactionInterval = time.Hour
+)
+
+// Store salva e legge la serie storica dei prezzi
+type Store struct {
+	db           *gorm.DB
+	retention    time.Duration
+	rawRetention time.Duration
+
+	mu            sync.Mutex
+	lastCompacted time.Time
+}
+
+// NewStore crea un archivio dei prezzi; le durate non positive vengono sostituite dai valori predefiniti
+func NewStore(db *gorm.DB, retention, rawRetention time.Duration) *Store {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if rawRetention <= 0 {
+		rawRetention = DefaultRawRetention
+	}
+
+	return &Store{
+		db:           db,
+		retention:    retention,
+		rawRetention: rawRetention,
+	}
+}
+
+// Record salva le quotazioni come singole osservazioni e, al massimo una volta ogni ora,
+// aggrega le osservazioni più vecchie ed elimina i campioni oltre la retention
+func (s *Store) Record(quotes ...pricing.Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	samples := make([]models.PriceSample, 0, len(quotes))
+	for _, quote := range quotes {
+		samples = append(samples, models.PriceSample{
+			CoinID:    quote.CoinID,
+			Currency:  quote.Currency,
+			SampledAt: quote.AsOf.UTC(),
+			Open:      quote.Price,
+			High:      quote.Price,
+			Low:       quote.Price,
+			Close:     quote.Price,
+		})
+	}
+
+	if err := s.db.Create(&samples).Error; err != nil {
+		return err
+	}
+
+	s.maybeCompact(time.Now().UTC())
+	return nil
+}
+
+// Samples restituisce i campioni di una criptovaluta nell'intervallo [from, to], in ordine cronologico
+func (s *Store) Samples(coinID, currency string, from, to time.Time) ([]models.PriceSample, error) {
+	var samples []models.PriceSample
+	err := s.db.Where("coin_id = ? AND currency = ? AND sampled_at >= ? AND sampled_at <= ?", coinID, currency, from.UTC(), to.UTC()).
+		Order("sampled_at ASC, id ASC").
+		Find(&samples).Error
+	return samples, err
+}
+
+// maybeCompact esegue Compact se è trascorso compactionInterval dall'ultima esecuzione
+func (s *Store) maybeCompact(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastCompacted) < compactionInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastCompacted = now
+	s.mu.Unlock()
+
+	if err := s.Compact(now); err != nil {
+		log.Printf("[Storico] Errore nella manutenzione dello storico prezzi: %v", err)
+	}
+}
+
+// Compact aggrega in campioni orari le osservazioni più vecchie della retention delle
+// osservazioni singole ed elimina tutti i campioni più vecchi della retention complessiva
+func (s *Store) Compact(now time.Time) error {
+	cutoff := now.Add(-s.rawRetention).Truncate(DownsampleResolution)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var raw []models.PriceSample
+		if err := tx.Where("resolution = ? AND sampled_at < ?", 0, cutoff).
+			Order("coin_id, currency, sampled_at, id").
+			Find(&raw).Error; err != nil {
+			return err
+		}
+
+		if len(raw) > 0 {
+			aggregated := downsample(raw, DownsampleResolution)
+			if err := tx.Create(&aggregated).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("resolution = ? AND sampled_at < ?", 0, cutoff).Delete(&models.PriceSample{}).Error; err != nil {
+				return err
+			}
+			log.Printf("[Storico] Aggregate %d osservazioni in %d campioni orari", len(raw), len(aggregated))
+		}
+
+		result := tx.Where("sampled_at < ?", now.Add(-s.retention)).Delete(&models.PriceSample{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("[Storico] Eliminati %d campioni oltre la retention", result.RowsAffected)
+		}
+
+		return nil
+	})
+}
+
+// downsample aggrega campioni ordinati per criptovaluta, valuta e data in intervalli di durata resolution
+func downsample(samples []models.PriceSample, resolution time.Duration) []models.PriceSample {
+	var result []models.PriceSample
+	for _, sample := range samples {
+		start := sample.SampledAt.Truncate(resolution)
+
+		if n := len(result); n > 0 {
+			last := &result[n-1]
+			if last.CoinID == sample.CoinID && last.Currency == sample.Currency && last.SampledAt.Equal(start) {
+				mergeSample(last, sample)
+				continue
+			}
+		}
+
+		sample.ID = 0
+		sample.SampledAt = start
+		sample.Resolution = int(resolution / time.Second)
+		result = append(result, sample)
+	}
+
+	return result
+}
+
+// mergeSample aggiunge a bucket un campione successivo dello stesso intervallo
+func mergeSample(bucket *models.PriceSample, sample models.PriceSample) {
+	bucket.High = max(bucket.High, sample.High)
+	bucket.Low = min(bucket.Low, sample.Low)
+	bucket.Close = sample.Close
+}
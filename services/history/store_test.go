@@ -0,0 +1,80 @@
+package history
+
+import (
+	"crypto-tracker/models"
+	"testing"
+	"time"
+)
+
+func TestDownsample(t *testing.T) {
+	withCoin := func(s models.PriceSample, coinID, currency string) models.PriceSample {
+		s.CoinID, s.Currency = coinID, currency
+		return s
+	}
+	hourly := int(time.Hour / time.Second)
+
+	tests := []struct {
+		name    string
+		samples []models.PriceSample
+		want    []models.PriceSample
+	}{
+		{
+			name: "nessuna osservazione",
+		},
+		{
+			name: "osservazioni della stessa ora",
+			samples: []models.PriceSample{
+				sample(5*time.Minute, 100, 100, 100, 100),
+				sample(25*time.Minute, 110, 110, 110, 110),
+				sample(55*time.Minute, 95, 95, 95, 95),
+			},
+			want: []models.PriceSample{
+				{CoinID: "bitcoin", Currency: "usd", SampledAt: start, Resolution: hourly, Open: 100, High: 110, Low: 95, Close: 95},
+			},
+		},
+		{
+			name: "ore diverse",
+			samples: []models.PriceSample{
+				sample(30*time.Minute, 100, 100, 100, 100),
+				sample(90*time.Minute, 120, 120, 120, 120),
+			},
+			want: []models.PriceSample{
+				{CoinID: "bitcoin", Currency: "usd", SampledAt: start, Resolution: hourly, Open: 100, High: 100, Low: 100, Close: 100},
+				{CoinID: "bitcoin", Currency: "usd", SampledAt: start.Add(time.Hour), Resolution: hourly, Open: 120, High: 120, Low: 120, Close: 120},
+			},
+		},
+		{
+			// La stessa ora di criptovalute o valute diverse resta in campioni separati
+			name: "criptovalute e valute diverse",
+			samples: []models.PriceSample{
+				sample(10*time.Minute, 100, 100, 100, 100),
+				withCoin(sample(10*time.Minute, 90, 90, 90, 90), "bitcoin", "eur"),
+				withCoin(sample(10*time.Minute, 3, 3, 3, 3), "ethereum", "usd"),
+				withCoin(sample(20*time.Minute, 4, 4, 4, 4), "ethereum", "usd"),
+			},
+			want: []models.PriceSample{
+				{CoinID: "bitcoin", Currency: "usd", SampledAt: start, Resolution: hourly, Open: 100, High: 100, Low: 100, Close: 100},
+				{CoinID: "bitcoin", Currency: "eur", SampledAt: start, Resolution: hourly, Open: 90, High: 90, Low: 90, Close: 90},
+				{CoinID: "ethereum", Currency: "usd", SampledAt: start, Resolution: hourly, Open: 3, High: 4, Low: 3, Close: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.samples {
+				tt.samples[i].ID = uint(i + 1)
+			}
+
+			got := downsample(tt.samples, DownsampleResolution)
+			if len(got) != len(tt.want) {
+				t.Fatalf("campioni = %+v, attesi %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("campione %d = %+v, atteso %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
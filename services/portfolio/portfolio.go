@@ -0,0 +1,303 @@
+package portfolio
+
+import (
+	"cmp"
+	"context"
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCurrencyMismatch indica una transazione in una valuta diversa da quella della posizione
+var ErrCurrencyMismatch = i18n.Errorf("portfolio.currency_mismatch")
+
+// Position è la valutazione di una posizione ai prezzi correnti
+type Position struct {
+	CryptoID             string  `json:"crypto_id"`
+	Currency             string  `json:"currency"`
+	Quantity             float64 `json:"quantity"`
+	AverageCost          float64 `json:"average_cost"`
+	CostBasis            float64 `json:"cost_basis"`
+	Price                float64 `json:"price"`
+	Value                float64 `json:"value"`
+	UnrealizedPnL        float64 `json:"unrealized_pnl"`
+	UnrealizedPnLPercent float64 `json:"unrealized_pnl_percent"`
+	RealizedPnL          float64 `json:"realized_pnl"`
+	Allocation           float64 `json:"allocation_percent"` // peso sul valore totale nella stessa valuta
+	PriceAvailable       bool    `json:"price_available"`
+}
+
+// Total riassume il portafoglio in una valuta
+type Total struct {
+	Currency             string  `json:"currency"`
+	Value                float64 `json:"value"`
+	CostBasis            float64 `json:"cost_basis"`
+	UnrealizedPnL        float64 `json:"unrealized_pnl"`
+	UnrealizedPnLPercent float64 `json:"unrealized_pnl_percent"`
+	RealizedPnL          float64 `json:"realized_pnl"`
+}
+
+// Summary è la valutazione completa del portafoglio di un utente
+type Summary struct {
+	Positions []Position `json:"positions"`
+	Totals    []Total    `json:"totals"`
+}
+
+// Holdings restituisce le posizioni di un utente
+func Holdings(db *gorm.DB, chatID int64) ([]models.Holding, error) {
+	var holdings []models.Holding
+	err := db.Where("user_chat_id = ?", chatID).Order("cryptocurrency_id").Find(&holdings).Error
+	return holdings, err
+}
+
+// HoldingsByChat restituisce le posizioni di più utenti, indicizzate per ID della chat
+func HoldingsByChat(db *gorm.DB, chatIDs []int64) (map[int64][]models.Holding, error) {
+	result := make(map[int64][]models.Holding, len(chatIDs))
+	if len(chatIDs) == 0 {
+		return result, nil
+	}
+
+	var holdings []models.Holding
+	if err := db.Where("user_chat_id IN ?", chatIDs).Order("cryptocurrency_id").Find(&holdings).Error; err != nil {
+		return nil, err
+	}
+	for _, holding := range holdings {
+		result[holding.UserChatID] = append(result[holding.UserChatID], holding)
+	}
+	return result, nil
+}
+
+// Current restituisce il valore attuale osservato per un alert sul portafoglio, valutando
+// le posizioni del proprietario ai prezzi correnti (es. alla creazione dell'alert)
+func Current(ctx context.Context, db *gorm.DB, prices pricing.PriceProvider, alert *models.Alert) (float64, error) {
+	holdings, err := Holdings(db, alert.UserChatID)
+	if err != nil {
+		return 0, err
+	}
+
+	summary, err := Valuate(ctx, prices, holdings)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := summary.AlertMetric(alert)
+	if !ok {
+		if alert.Type == models.AlertTypePositionPnL {
+			return 0, i18n.Errorf("portfolio.no_position", alert.CryptoID)
+		}
+		return 0, i18n.Errorf("portfolio.no_valued_position", alert.Currency)
+	}
+	return value, nil
+}
+
+// Record registra una transazione e aggiorna la posizione corrispondente in un'unica transazione del database.
+// La riga della posizione viene bloccata fino al commit, così che transazioni concorrenti sulla stessa
+// criptovaluta non si sovrascrivano, e la posizione viene ricalcolata da tutte le transazioni in ordine di
+// esecuzione: una transazione retrodatata (executed_at nel passato) entra al suo posto nella cronologia.
+func Record(db *gorm.DB, tx models.Transaction) (models.Transaction, models.Holding, error) {
+	if tx.Quantity <= 0 || tx.Price <= 0 {
+		return tx, models.Holding{}, i18n.Errorf("portfolio.invalid_trade")
+	}
+
+	now := time.Now().UTC()
+	if tx.ExecutedAt.IsZero() {
+		tx.ExecutedAt = now
+	}
+	tx.CreatedAt = now
+
+	var holding models.Holding
+	err := db.Transaction(func(dbtx *gorm.DB) error {
+		// Crea la posizione se manca, così che il blocco seguente serializzi anche il primo acquisto
+		empty := models.Holding{
+			UserChatID: tx.UserChatID,
+			CryptoID:   tx.CryptoID,
+			Currency:   tx.Currency,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := dbtx.Clauses(clause.OnConflict{DoNothing: true}).Create(&empty).Error; err != nil {
+			return err
+		}
+
+		err := dbtx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_chat_id = ? AND cryptocurrency_id = ?", tx.UserChatID, tx.CryptoID).
+			First(&holding).Error
+		if err != nil {
+			return err
+		}
+
+		var history []models.Transaction
+		err = dbtx.Where("user_chat_id = ? AND cryptocurrency_id = ?", tx.UserChatID, tx.CryptoID).
+			Order("executed_at, id").
+			Find(&history).Error
+		if err != nil {
+			return err
+		}
+
+		if err := rebuild(&holding, append(history, tx)); err != nil {
+			return err
+		}
+		holding.UpdatedAt = now
+
+		if err := dbtx.Save(&holding).Error; err != nil {
+			return err
+		}
+		return dbtx.Create(&tx).Error
+	})
+
+	return tx, holding, err
+}
+
+// rebuild ricalcola la posizione applicando le transazioni in ordine di esecuzione; a parità di
+// orario conta l'ordine di registrazione. Una posizione chiusa può ripartire in un'altra valuta:
+// il profitto realizzato, espresso nella valuta precedente, non è confrontabile e resta solo nello
+// storico delle transazioni.
+func rebuild(holding *models.Holding, transactions []models.Transaction) error {
+	transactions = slices.Clone(transactions)
+	slices.SortStableFunc(transactions, func(a, b models.Transaction) int {
+		return a.ExecutedAt.Compare(b.ExecutedAt)
+	})
+
+	holding.Quantity, holding.CostBasis, holding.RealizedPnL = 0, 0, 0
+	for _, tx := range transactions {
+		if tx.Currency != holding.Currency {
+			if holding.Quantity > 0 {
+				return i18n.Errorf("portfolio.currency_mismatch_detail", ErrCurrencyMismatch, tx.CryptoID, holding.Currency)
+			}
+			holding.Currency = tx.Currency
+			holding.CostBasis, holding.RealizedPnL = 0, 0
+		}
+
+		if err := holding.Apply(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Valuate valuta le posizioni ai prezzi correnti, con una richiesta di prezzi per valuta
+func Valuate(ctx context.Context, prices pricing.PriceProvider, holdings []models.Holding) (Summary, error) {
+	// Raggruppa le criptovalute per valuta di valutazione
+	coinIDs := make(map[string][]string)
+	for _, holding := range holdings {
+		if holding.Quantity > 0 && !slices.Contains(coinIDs[holding.Currency], holding.CryptoID) {
+			coinIDs[holding.Currency] = append(coinIDs[holding.Currency], holding.CryptoID)
+		}
+	}
+
+	snapshot := make(map[string]map[string]float64, len(coinIDs))
+	for currency, ids := range coinIDs {
+		result, err := prices.GetPrices(ctx, ids, currency)
+		if err != nil {
+			return Summary{}, err
+		}
+		snapshot[currency] = result
+	}
+
+	return Evaluate(holdings, snapshot), nil
+}
+
+// Evaluate valuta le posizioni data un'istantanea di prezzi indicizzata per valuta e ID.
+// Le posizioni chiuse contribuiscono solo al profitto realizzato.
+func Evaluate(holdings []models.Holding, snapshot map[string]map[string]float64) Summary {
+	var summary Summary
+	totals := make(map[string]*Total)
+
+	for _, holding := range holdings {
+		total, ok := totals[holding.Currency]
+		if !ok {
+			total = &Total{Currency: holding.Currency}
+			totals[holding.Currency] = total
+		}
+		total.RealizedPnL += holding.RealizedPnL
+
+		if holding.Quantity <= 0 {
+			continue
+		}
+
+		position := Position{
+			CryptoID:    holding.CryptoID,
+			Currency:    holding.Currency,
+			Quantity:    holding.Quantity,
+			AverageCost: holding.AverageCost(),
+			CostBasis:   holding.CostBasis,
+			RealizedPnL: holding.RealizedPnL,
+		}
+
+		if price, ok := snapshot[holding.Currency][holding.CryptoID]; ok {
+			position.PriceAvailable = true
+			position.Price = price
+			position.Value = price * holding.Quantity
+			position.UnrealizedPnL = position.Value - holding.CostBasis
+			position.UnrealizedPnLPercent = percentOf(position.UnrealizedPnL, holding.CostBasis)
+
+			total.Value += position.Value
+			total.CostBasis += holding.CostBasis
+			total.UnrealizedPnL += position.UnrealizedPnL
+		}
+
+		summary.Positions = append(summary.Positions, position)
+	}
+
+	for i := range summary.Positions {
+		position := &summary.Positions[i]
+		position.Allocation = percentOf(position.Value, totals[position.Currency].Value)
+	}
+
+	// Posizioni dalla più grande alla più piccola, totali in ordine di valuta
+	slices.SortStableFunc(summary.Positions, func(a, b Position) int {
+		return cmp.Compare(b.Value, a.Value)
+	})
+
+	for _, total := range totals {
+		total.UnrealizedPnLPercent = percentOf(total.UnrealizedPnL, total.CostBasis)
+		summary.Totals = append(summary.Totals, *total)
+	}
+	slices.SortFunc(summary.Totals, func(a, b Total) int {
+		return cmp.Compare(a.Currency, b.Currency)
+	})
+
+	return summary
+}
+
+// percentOf restituisce part in percentuale di whole (0 se whole è nullo)
+func percentOf(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return part / whole * 100
+}
+
+// AlertMetric restituisce il valore osservato per un alert sul portafoglio: il valore totale
+// nella valuta dell'alert oppure il P&L percentuale della posizione. Restituisce false se il
+// valore non è calcolabile (posizione assente o prezzi mancanti).
+func (s Summary) AlertMetric(alert *models.Alert) (float64, bool) {
+	switch alert.Type {
+	case models.AlertTypePortfolioValue:
+		// Con un prezzo mancante il totale risulterebbe falsamente basso
+		for _, position := range s.Positions {
+			if position.Currency == alert.Currency && !position.PriceAvailable {
+				return 0, false
+			}
+		}
+		for _, total := range s.Totals {
+			if total.Currency == alert.Currency {
+				return total.Value, true
+			}
+		}
+
+	case models.AlertTypePositionPnL:
+		for _, position := range s.Positions {
+			if position.CryptoID == alert.CryptoID && position.PriceAvailable {
+				return position.UnrealizedPnLPercent, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,111 @@
+package portfolio
+
+import (
+	"crypto-tracker/models"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func trade(kind string, quantity, price float64, currency string, at time.Time) models.Transaction {
+	return models.Transaction{
+		CryptoID:   "bitcoin",
+		Type:       kind,
+		Quantity:   quantity,
+		Price:      price,
+		Currency:   currency,
+		ExecutedAt: at,
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		transactions []models.Transaction
+		wantQuantity float64
+		wantCost     float64
+		wantRealized float64
+		wantCurrency string
+		wantErr      error
+	}{
+		{
+			name: "ordine di registrazione",
+			transactions: []models.Transaction{
+				trade(models.TransactionBuy, 1, 100, "usd", day),
+				trade(models.TransactionBuy, 1, 200, "usd", day.Add(time.Hour)),
+				trade(models.TransactionSell, 1, 300, "usd", day.Add(2*time.Hour)),
+			},
+			wantQuantity: 1, wantCost: 150, wantRealized: 150, wantCurrency: "usd",
+		},
+		{
+			// L'acquisto retrodatato entra prima della vendita e ne cambia il costo medio
+			name: "acquisto retrodatato",
+			transactions: []models.Transaction{
+				trade(models.TransactionBuy, 1, 100, "usd", day),
+				trade(models.TransactionSell, 1, 300, "usd", day.Add(2*time.Hour)),
+				trade(models.TransactionBuy, 1, 200, "usd", day.Add(time.Hour)),
+			},
+			wantQuantity: 1, wantCost: 150, wantRealized: 150, wantCurrency: "usd",
+		},
+		{
+			name: "vendita retrodatata prima degli acquisti",
+			transactions: []models.Transaction{
+				trade(models.TransactionBuy, 1, 100, "usd", day),
+				trade(models.TransactionSell, 1, 300, "usd", day.Add(-time.Hour)),
+			},
+			wantErr: models.ErrInsufficientQuantity,
+		},
+		{
+			name: "stesso orario nell'ordine di registrazione",
+			transactions: []models.Transaction{
+				trade(models.TransactionBuy, 1, 100, "usd", day),
+				trade(models.TransactionSell, 1, 300, "usd", day),
+			},
+			wantQuantity: 0, wantCost: 0, wantRealized: 200, wantCurrency: "usd",
+		},
+		{
+			name: "posizione chiusa riaperta in un'altra valuta",
+			transactions: []models.Transaction{
+				trade(models.TransactionBuy, 1, 100, "usd", day),
+				trade(models.TransactionSell, 1, 300, "usd", day.Add(time.Hour)),
+				trade(models.TransactionBuy, 2, 90, "eur", day.Add(2*time.Hour)),
+			},
+			wantQuantity: 2, wantCost: 180, wantRealized: 0, wantCurrency: "eur",
+		},
+		{
+			name: "valuta diversa con posizione aperta",
+			transactions: []models.Transaction{
+				trade(models.TransactionBuy, 1, 100, "usd", day),
+				trade(models.TransactionBuy, 1, 90, "eur", day.Add(time.Hour)),
+			},
+			wantErr: ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holding := models.Holding{CryptoID: "bitcoin", Currency: "usd", Quantity: 5, CostBasis: 1}
+			err := rebuild(&holding, tt.transactions)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("errore = %v, atteso %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("errore inatteso: %v", err)
+			}
+			if math.Abs(holding.Quantity-tt.wantQuantity) > 1e-9 ||
+				math.Abs(holding.CostBasis-tt.wantCost) > 1e-9 ||
+				math.Abs(holding.RealizedPnL-tt.wantRealized) > 1e-9 ||
+				holding.Currency != tt.wantCurrency {
+				t.Fatalf("posizione = %+v, attesa quantità %v, costo %v, realizzato %v in %s",
+					holding, tt.wantQuantity, tt.wantCost, tt.wantRealized, tt.wantCurrency)
+			}
+		})
+	}
+}
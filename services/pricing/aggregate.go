@@ -0,0 +1,226 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultMaxDeviation è lo scostamento massimo dalla mediana (in frazione, 0.02 = 2%)
+// oltre il quale il prezzo di una sorgente viene scartato come anomalo
+const DefaultMaxDeviation = 0.02
+
+// Fallback interroga le sorgenti in ordine e passa alla successiva solo per le
+// criptovalute che la precedente non ha restituito o quando questa fallisce
+type Fallback struct {
+	sources []PriceProvider
+}
+
+// NewFallback crea una catena di sorgenti interrogate in ordine di priorità
+func NewFallback(sources ...PriceProvider) *Fallback {
+	return &Fallback{sources: sources}
+}
+
+// Name restituisce il nome della sorgente
+func (f *Fallback) Name() string {
+	return "fallback"
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta nella valuta indicata
+func (f *Fallback) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	return getSinglePrice(ctx, f, coinID, currency)
+}
+
+// GetPrices restituisce i prezzi nella valuta indicata, completando quelli mancanti con le sorgenti successive
+func (f *Fallback) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	quotes, err := f.GetQuotes(ctx, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return pricesFromQuotes(quotes), nil
+}
+
+// GetQuote restituisce la quotazione di una singola criptovaluta
+func (f *Fallback) GetQuote(ctx context.Context, coinID, currency string) (Quote, error) {
+	return getSingleQuote(ctx, f, coinID, currency)
+}
+
+// GetQuotes restituisce le quotazioni nella valuta indicata, completando quelle mancanti con le sorgenti successive
+func (f *Fallback) GetQuotes(ctx context.Context, coinIDs []string, currency string) (map[string]Quote, error) {
+	quotes := make(map[string]Quote, len(coinIDs))
+	missing := slices.Clone(coinIDs)
+	var errs []error
+
+	for _, source := range f.sources {
+		if len(missing) == 0 {
+			break
+		}
+
+		result, err := GetQuotes(ctx, source, missing, currency)
+		if err != nil {
+			log.Printf("[Prezzi] Sorgente %s non disponibile, passo alla successiva: %v", sourceName(source), err)
+			errs = append(errs, fmt.Errorf("%s: %w", sourceName(source), err))
+			continue
+		}
+
+		remaining := missing[:0]
+		for _, id := range missing {
+			if quote, ok := result[id]; ok {
+				quotes[id] = quote
+			} else {
+				remaining = append(remaining, id)
+			}
+		}
+		missing = remaining
+	}
+
+	// Se nessuna sorgente ha risposto restituisci l'errore, altrimenti i prezzi parziali
+	if len(quotes) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return quotes, nil
+}
+
+// Median interroga tutte le sorgenti in parallelo e restituisce, per ogni criptovaluta,
+// la mediana dei prezzi dopo aver scartato quelli troppo distanti dalle altre sorgenti
+type Median struct {
+	sources      []PriceProvider
+	maxDeviation float64
+}
+
+// NewMedian crea un aggregatore a mediana; se maxDeviation non è positivo usa DefaultMaxDeviation
+func NewMedian(maxDeviation float64, sources ...PriceProvider) *Median {
+	if maxDeviation <= 0 {
+		maxDeviation = DefaultMaxDeviation
+	}
+
+	return &Median{
+		sources:      sources,
+		maxDeviation: maxDeviation,
+	}
+}
+
+// Name restituisce il nome della sorgente
+func (m *Median) Name() string {
+	return "median"
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta nella valuta indicata
+func (m *Median) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	return getSinglePrice(ctx, m, coinID, currency)
+}
+
+// GetPrices restituisce il prezzo di consenso di più criptovalute nella valuta indicata
+func (m *Median) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	quotes, err := m.GetQuotes(ctx, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return pricesFromQuotes(quotes), nil
+}
+
+// GetQuote restituisce la quotazione di consenso di una singola criptovaluta
+func (m *Median) GetQuote(ctx context.Context, coinID, currency string) (Quote, error) {
+	return getSingleQuote(ctx, m, coinID, currency)
+}
+
+// GetQuotes restituisce le quotazioni di consenso di più criptovalute nella valuta indicata.
+// La variazione nelle 24 ore è presa dalla prima sorgente che la fornisce.
+func (m *Median) GetQuotes(ctx context.Context, coinIDs []string, currency string) (map[string]Quote, error) {
+	results := make([]map[string]Quote, len(m.sources))
+	errs := make([]error, len(m.sources))
+
+	var wg sync.WaitGroup
+	for i, source := range m.sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = GetQuotes(ctx, source, coinIDs, currency)
+			if errs[i] != nil {
+				log.Printf("[Prezzi] Sorgente %s esclusa dalla mediana: %v", sourceName(source), errs[i])
+				errs[i] = fmt.Errorf("%s: %w", sourceName(source), errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	asOf := time.Now()
+	quotes := make(map[string]Quote, len(coinIDs))
+	for _, id := range coinIDs {
+		var prices []float64
+		var change *float64
+		for _, result := range results {
+			if quote, ok := result[id]; ok {
+				prices = append(prices, quote.Price)
+				if change == nil {
+					change = quote.Change24h
+				}
+			}
+		}
+
+		price, ok := consensusPrice(prices, m.maxDeviation)
+		if !ok {
+			if len(prices) > 0 {
+				log.Printf("[Prezzi] Nessun consenso tra le sorgenti per %s (%v), prezzo escluso", id, prices)
+			}
+			continue
+		}
+		quotes[id] = Quote{CoinID: id, Currency: currency, Price: price, Change24h: change, AsOf: asOf}
+	}
+
+	if len(quotes) == 0 {
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+	}
+
+	return quotes, nil
+}
+
+// consensusPrice calcola la mediana dei prezzi, scarta quelli che se ne discostano più di
+// maxDeviation e restituisce la mediana dei prezzi rimanenti
+func consensusPrice(quotes []float64, maxDeviation float64) (float64, bool) {
+	if len(quotes) == 0 {
+		return 0, false
+	}
+
+	center := median(quotes)
+	if center == 0 {
+		return 0, false
+	}
+
+	inliers := make([]float64, 0, len(quotes))
+	for _, q := range quotes {
+		if math.Abs(q-center)/center <= maxDeviation {
+			inliers = append(inliers, q)
+		}
+	}
+
+	// Se nessuna sorgente è vicina alla mediana (es. due sole sorgenti in disaccordo) non è possibile
+	// stabilire quale sia anomala: la mediana non è un prezzo reale e la criptovaluta viene saltata
+	if len(inliers) == 0 {
+		return 0, false
+	}
+
+	return median(inliers), true
+}
+
+// median restituisce la mediana di un insieme non vuoto di valori
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,109 @@
+package pricing_test
+
+import (
+	"context"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/pricing/pricingtest"
+	"errors"
+	"testing"
+)
+
+func TestFallbackUsesNextSourceForMissingPrices(t *testing.T) {
+	primary := pricingtest.New("primary").Set("bitcoin", "usd", 65000)
+	secondary := pricingtest.New("secondary").Set("bitcoin", "usd", 64000).Set("ethereum", "usd", 3200)
+
+	quotes, err := pricing.NewFallback(primary, secondary).GetQuotes(context.Background(), []string{"bitcoin", "ethereum"}, "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quotes["bitcoin"].Price != 65000 || quotes["ethereum"].Price != 3200 {
+		t.Fatalf("quotazioni inattese: %v", quotes)
+	}
+}
+
+func TestFallbackSkipsFailingSource(t *testing.T) {
+	primary := pricingtest.New("primary")
+	primary.Fail(errors.New("timeout"))
+	secondary := pricingtest.New("secondary").Set("bitcoin", "usd", 64000)
+
+	price, err := pricing.NewFallback(primary, secondary).GetPrice(context.Background(), "bitcoin", "usd")
+	if err != nil || price != 64000 {
+		t.Fatalf("prezzo = %v, err = %v", price, err)
+	}
+}
+
+func TestFallbackStopsWhenComplete(t *testing.T) {
+	primary := pricingtest.New("primary").Set("bitcoin", "usd", 65000)
+	secondary := pricingtest.New("secondary").Set("bitcoin", "usd", 64000)
+
+	if _, err := pricing.NewFallback(primary, secondary).GetPrice(context.Background(), "bitcoin", "usd"); err != nil {
+		t.Fatal(err)
+	}
+	if n := secondary.Calls(); n != 0 {
+		t.Fatalf("richieste alla seconda sorgente = %d, attese 0", n)
+	}
+}
+
+func TestFallbackAllSourcesFailing(t *testing.T) {
+	primary, secondary := pricingtest.New("primary"), pricingtest.New("secondary")
+	primary.Fail(errors.New("timeout"))
+	secondary.Fail(errors.New("429"))
+
+	if _, err := pricing.NewFallback(primary, secondary).GetPrices(context.Background(), []string{"bitcoin"}, "usd"); err == nil {
+		t.Fatal("errore atteso se nessuna sorgente risponde")
+	}
+}
+
+func TestMedianDiscardsOutliers(t *testing.T) {
+	a := pricingtest.New("a").Set("bitcoin", "usd", 65000).SetChange24h("bitcoin", "usd", 1.5)
+	b := pricingtest.New("b").Set("bitcoin", "usd", 65100)
+	c := pricingtest.New("c").Set("bitcoin", "usd", 90000)
+
+	quote, err := pricing.NewMedian(0.02, a, b, c).GetQuote(context.Background(), "bitcoin", "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Price != 65050 {
+		t.Fatalf("prezzo = %v, atteso 65050 (mediana senza il valore anomalo)", quote.Price)
+	}
+	if quote.Change24h == nil || *quote.Change24h != 1.5 {
+		t.Fatalf("variazione 24h = %v, attesa 1.5", quote.Change24h)
+	}
+}
+
+func TestMedianSkipsCoinsWithoutConsensus(t *testing.T) {
+	a := pricingtest.New("a").Set("bitcoin", "usd", 60000).Set("ethereum", "usd", 3200)
+	b := pricingtest.New("b").Set("bitcoin", "usd", 70000).Set("ethereum", "usd", 3210)
+
+	prices, err := pricing.NewMedian(0.02, a, b).GetPrices(context.Background(), []string{"bitcoin", "ethereum"}, "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := prices["bitcoin"]; ok {
+		t.Fatalf("bitcoin non dovrebbe avere un prezzo di consenso: %v", prices)
+	}
+	if prices["ethereum"] != 3205 {
+		t.Fatalf("prezzo di ethereum = %v, atteso 3205", prices["ethereum"])
+	}
+}
+
+func TestMedianToleratesFailingSource(t *testing.T) {
+	a := pricingtest.New("a").Set("bitcoin", "usd", 65000)
+	b := pricingtest.New("b")
+	b.Fail(errors.New("timeout"))
+
+	price, err := pricing.NewMedian(0.02, a, b).GetPrice(context.Background(), "bitcoin", "usd")
+	if err != nil || price != 65000 {
+		t.Fatalf("prezzo = %v, err = %v", price, err)
+	}
+}
+
+func TestMedianAllSourcesFailing(t *testing.T) {
+	a, b := pricingtest.New("a"), pricingtest.New("b")
+	a.Fail(errors.New("timeout"))
+	b.Fail(errors.New("429"))
+
+	if _, err := pricing.NewMedian(0.02, a, b).GetQuotes(context.Background(), []string{"bitcoin"}, "usd"); err == nil {
+		t.Fatal("errore atteso se nessuna sorgente risponde")
+	}
+}
@@ -0,0 +1,185 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultBinanceBaseURL è l'URL base dell'API pubblica di Binance
+const DefaultBinanceBaseURL = "https://api.binance.com"
+
+// binanceQuotes associa le valute supportate all'asset di quotazione delle coppie Binance.
+// I prezzi in USD sono approssimati con le coppie quotate in USDT.
+var binanceQuotes = map[string]string{
+	"usd": "USDT",
+	"eur": "EUR",
+	"btc": "BTC",
+	"eth": "ETH",
+}
+
+// binanceInvalidSymbol è il codice di errore restituito da Binance per una coppia inesistente
+const binanceInvalidSymbol = -1121
+
+// binanceTicker è il prezzo di una coppia restituito da /api/v3/ticker/price
+type binanceTicker struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+// binanceError è il corpo delle risposte di errore di Binance
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// Binance implementa PriceProvider usando l'endpoint /api/v3/ticker/price
+type Binance struct {
+	client  *resty.Client
+	invalid pairSet // Coppie che Binance non riconosce
+}
+
+// NewBinance crea un client Binance; se baseURL è vuoto usa l'API pubblica
+func NewBinance(baseURL string) *Binance {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+
+	return &Binance{
+		client: resty.New().
+			SetBaseURL(strings.TrimRight(baseURL, "/")).
+			SetTimeout(15 * time.Second),
+	}
+}
+
+// Name restituisce il nome della sorgente
+func (b *Binance) Name() string {
+	return "binance"
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta nella valuta indicata
+func (b *Binance) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	return getSinglePrice(ctx, b, coinID, currency)
+}
+
+// GetPrices restituisce i prezzi delle criptovalute supportate con una sola richiesta
+func (b *Binance) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(coinIDs))
+
+	quote, ok := binanceQuotes[currency]
+	if !ok {
+		return prices, nil
+	}
+
+	// Associa ogni coppia di trading all'ID CoinGecko corrispondente
+	pairs := make(map[string]string, len(coinIDs))
+	symbols := make([]string, 0, len(coinIDs))
+	for _, id := range coinIDs {
+		symbol, ok := tickerSymbol(id)
+		if !ok || symbol == quote {
+			continue
+		}
+		pair := symbol + quote
+		if _, dup := pairs[pair]; dup || b.invalid.has(pair) {
+			continue
+		}
+		pairs[pair] = id
+		symbols = append(symbols, pair)
+	}
+
+	if len(symbols) == 0 {
+		return prices, nil
+	}
+
+	tickers, err := b.tickers(ctx, symbols)
+	var apiErr *binanceError
+	if errors.As(err, &apiErr) && apiErr.Code == binanceInvalidSymbol {
+		// Una sola coppia sconosciuta fa fallire l'intera richiesta: le coppie vengono richieste
+		// una alla volta per individuare quelle non valide
+		tickers, err = b.tickersOneByOne(ctx, symbols)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ticker := range tickers {
+		id, ok := pairs[ticker.Symbol]
+		if !ok {
+			continue
+		}
+		price, err := strconv.ParseFloat(ticker.Price, 64)
+		if err != nil {
+			log.Printf("[Binance] Prezzo non valido per %s: %q", ticker.Symbol, ticker.Price)
+			continue
+		}
+		prices[id] = price
+	}
+
+	return prices, nil
+}
+
+// tickers richiede i prezzi di più coppie con una sola richiesta
+func (b *Binance) tickers(ctx context.Context, symbols []string) ([]binanceTicker, error) {
+	encoded, err := json.Marshal(symbols)
+	if err != nil {
+		return nil, fmt.Errorf("errore nella codifica dei simboli Binance: %w", err)
+	}
+
+	var result []binanceTicker
+	err = b.get(ctx, map[string]string{"symbols": string(encoded)}, &result)
+	return result, err
+}
+
+// tickersOneByOne richiede i prezzi delle coppie singolarmente, escludendo d'ora in poi quelle sconosciute
+func (b *Binance) tickersOneByOne(ctx context.Context, symbols []string) ([]binanceTicker, error) {
+	result := make([]binanceTicker, 0, len(symbols))
+	for _, symbol := range symbols {
+		var ticker binanceTicker
+		err := b.get(ctx, map[string]string{"symbol": symbol}, &ticker)
+
+		var apiErr *binanceError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.Code == binanceInvalidSymbol:
+			log.Printf("[Binance] Coppia %s non disponibile, esclusa dalle prossime richieste", symbol)
+			b.invalid.add(symbol)
+		case err != nil:
+			return nil, err
+		default:
+			result = append(result, ticker)
+		}
+	}
+	return result, nil
+}
+
+// get esegue una richiesta a /api/v3/ticker/price; gli errori di Binance sono restituiti come *binanceError
+func (b *Binance) get(ctx context.Context, params map[string]string, result any) error {
+	var apiErr binanceError
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		SetResult(result).
+		SetError(&apiErr).
+		Get("/api/v3/ticker/price")
+	if err != nil {
+		return fmt.Errorf("errore nella richiesta a Binance: %w", err)
+	}
+	if resp.IsError() {
+		if apiErr.Code != 0 {
+			return &apiErr
+		}
+		return fmt.Errorf("risposta non valida da Binance: %s", resp.Status())
+	}
+	return nil
+}
+
+// Error restituisce il messaggio di errore di Binance
+func (e *binanceError) Error() string {
+	return fmt.Sprintf("errore restituito da Binance (%d): %s", e.Code, e.Msg)
+}
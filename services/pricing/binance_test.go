@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newBinanceServer avvia un finto server Binance che conosce solo le coppie indicate
+// e, come quello reale, rifiuta l'intera richiesta se una coppia non esiste
+func newBinanceServer(t *testing.T, known map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/v3/ticker/price" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var symbols []string
+		if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+			symbols = []string{symbol}
+		} else if err := json.Unmarshal([]byte(r.URL.Query().Get("symbols")), &symbols); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var result []binanceTicker
+		for _, symbol := range symbols {
+			price, ok := known[symbol]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(binanceError{Code: binanceInvalidSymbol, Msg: "Invalid symbol."})
+				return
+			}
+			result = append(result, binanceTicker{Symbol: symbol, Price: price})
+		}
+
+		if r.URL.Query().Has("symbol") {
+			json.NewEncoder(w).Encode(result[0])
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestBinanceGetPrices(t *testing.T) {
+	server, _ := newBinanceServer(t, map[string]string{"BTCUSDT": "65000.5", "ETHUSDT": "3200"})
+
+	prices, err := NewBinance(server.URL).GetPrices(context.Background(), []string{"bitcoin", "ethereum", "unknown-coin"}, "usd")
+	if err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	if len(prices) != 2 || prices["bitcoin"] != 65000.5 || prices["ethereum"] != 3200 {
+		t.Fatalf("prezzi inattesi: %v", prices)
+	}
+}
+
+func TestBinanceGetPricesSkipsInvalidSymbols(t *testing.T) {
+	server, requests := newBinanceServer(t, map[string]string{"BTCUSDT": "65000", "ETHUSDT": "3200"})
+	binance := NewBinance(server.URL)
+	coinIDs := []string{"bitcoin", "matic-network", "ethereum"}
+
+	prices, err := binance.GetPrices(context.Background(), coinIDs, "usd")
+	if err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	if len(prices) != 2 || prices["bitcoin"] != 65000 || prices["ethereum"] != 3200 {
+		t.Fatalf("prezzi inattesi: %v", prices)
+	}
+
+	// La coppia non valida viene esclusa: la richiesta successiva è di nuovo una sola
+	requests.Store(0)
+	if _, err := binance.GetPrices(context.Background(), coinIDs, "usd"); err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("richieste = %d, attesa 1", n)
+	}
+}
+
+func TestBinanceGetPricesServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewBinance(server.URL).GetPrices(context.Background(), []string{"bitcoin"}, "usd")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("errore atteso per risposta 503, ottenuto %v", err)
+	}
+}
+
+func TestBinanceGetPricesInCurrency(t *testing.T) {
+	server, _ := newBinanceServer(t, map[string]string{"BTCEUR": "60000", "ETHBTC": "0.05"})
+	binance := NewBinance(server.URL)
+
+	prices, err := binance.GetPrices(context.Background(), []string{"bitcoin"}, "eur")
+	if err != nil || len(prices) != 1 || prices["bitcoin"] != 60000 {
+		t.Fatalf("prezzi = %v, err = %v", prices, err)
+	}
+
+	// Una criptovaluta non ha prezzo in sé stessa
+	prices, err = binance.GetPrices(context.Background(), []string{"ethereum", "bitcoin"}, "btc")
+	if err != nil || len(prices) != 1 || prices["ethereum"] != 0.05 {
+		t.Fatalf("prezzi = %v, err = %v", prices, err)
+	}
+}
+
+func TestBinanceGetPricesUnsupportedCurrency(t *testing.T) {
+	server, requests := newBinanceServer(t, nil)
+
+	prices, err := NewBinance(server.URL).GetPrices(context.Background(), []string{"bitcoin"}, "chf")
+	if err != nil || len(prices) != 0 || requests.Load() != 0 {
+		t.Fatalf("prezzi = %v, err = %v, richieste = %d", prices, err, requests.Load())
+	}
+}
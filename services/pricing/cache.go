@@ -0,0 +1,129 @@
+package pricing
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheTTL è la durata di validità predefinita di un prezzo in cache
+const DefaultCacheTTL = time.Minute
+
+// Cache è un PriceProvider che mantiene in memoria i prezzi ottenuti da un'altra sorgente
+// per una durata configurabile e unisce le richieste concorrenti per le stesse criptovalute.
+type Cache struct {
+	source PriceProvider
+	ttl    time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]Quote // indicizzate per "valuta:id"
+
+	group singleflight.Group
+}
+
+// NewCache crea una cache davanti a source; se ttl non è positivo usa DefaultCacheTTL
+func NewCache(source PriceProvider, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Cache{
+		source:  source,
+		ttl:     ttl,
+		entries: make(map[string]Quote),
+	}
+}
+
+// GetPrice restituisce il prezzo di una criptovaluta, dalla cache se ancora valido
+func (c *Cache) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	quote, err := c.GetQuote(ctx, coinID, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	return quote.Price, nil
+}
+
+// GetPrices restituisce i prezzi di più criptovalute, richiedendo alla sorgente solo quelli scaduti
+func (c *Cache) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	quotes, err := c.GetQuotes(ctx, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return pricesFromQuotes(quotes), nil
+}
+
+// GetQuote restituisce il prezzo di una criptovaluta con il relativo timestamp
+func (c *Cache) GetQuote(ctx context.Context, coinID, currency string) (Quote, error) {
+	return getSingleQuote(ctx, c, coinID, currency)
+}
+
+// GetQuotes restituisce i prezzi di più criptovalute con il relativo timestamp
+func (c *Cache) GetQuotes(ctx context.Context, coinIDs []string, currency string) (map[string]Quote, error) {
+	quotes := make(map[string]Quote, len(coinIDs))
+	var missing []string
+
+	now := time.Now()
+	c.mu.RLock()
+	for _, id := range coinIDs {
+		if quote, ok := c.entries[cacheKey(id, currency)]; ok && now.Sub(quote.AsOf) < c.ttl {
+			quotes[id] = quote
+		} else if !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return quotes, nil
+	}
+
+	fetched, err := c.fetch(ctx, missing, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, quote := range fetched {
+		quotes[id] = quote
+	}
+
+	return quotes, nil
+}
+
+// fetch richiede i prezzi alla sorgente; richieste concorrenti per lo stesso insieme
+// di criptovalute e la stessa valuta condividono un'unica chiamata
+func (c *Cache) fetch(ctx context.Context, coinIDs []string, currency string) (map[string]Quote, error) {
+	sorted := slices.Clone(coinIDs)
+	slices.Sort(sorted)
+	key := currency + ":" + strings.Join(sorted, ",")
+
+	result, err, _ := c.group.Do(key, func() (any, error) {
+		fetched, err := GetQuotes(ctx, c.source, sorted, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		for id, quote := range fetched {
+			c.entries[cacheKey(id, currency)] = quote
+		}
+		c.mu.Unlock()
+
+		return fetched, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[string]Quote), nil
+}
+
+// cacheKey restituisce la chiave di cache per una criptovaluta in una valuta
+func cacheKey(coinID, currency string) string {
+	return currency + ":" + coinID
+}
@@ -0,0 +1,130 @@
+package pricing_test
+
+import (
+	"context"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/pricing/pricingtest"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheServesFreshPrices(t *testing.T) {
+	source := pricingtest.New("fake").Set("bitcoin", "usd", 65000)
+	cache := pricing.NewCache(source, time.Minute)
+
+	for range 3 {
+		price, err := cache.GetPrice(context.Background(), "bitcoin", "usd")
+		if err != nil || price != 65000 {
+			t.Fatalf("prezzo = %v, err = %v", price, err)
+		}
+	}
+	if n := source.Calls(); n != 1 {
+		t.Fatalf("richieste alla sorgente = %d, attesa 1", n)
+	}
+}
+
+func TestCacheRefreshesExpiredPrices(t *testing.T) {
+	source := pricingtest.New("fake").Set("bitcoin", "usd", 65000)
+	cache := pricing.NewCache(source, 10*time.Millisecond)
+
+	if _, err := cache.GetPrice(context.Background(), "bitcoin", "usd"); err != nil {
+		t.Fatal(err)
+	}
+	source.Set("bitcoin", "usd", 66000)
+	time.Sleep(20 * time.Millisecond)
+
+	price, err := cache.GetPrice(context.Background(), "bitcoin", "usd")
+	if err != nil || price != 66000 {
+		t.Fatalf("prezzo = %v, err = %v, atteso 66000", price, err)
+	}
+	if n := source.Calls(); n != 2 {
+		t.Fatalf("richieste alla sorgente = %d, attese 2", n)
+	}
+}
+
+func TestCacheRequestsOnlyMissingPrices(t *testing.T) {
+	source := pricingtest.New("fake").Set("bitcoin", "usd", 65000).Set("ethereum", "usd", 3200)
+	cache := pricing.NewCache(source, time.Minute)
+
+	if _, err := cache.GetPrice(context.Background(), "bitcoin", "usd"); err != nil {
+		t.Fatal(err)
+	}
+	source.Set("bitcoin", "usd", 66000)
+
+	// Bitcoin è ancora in cache, solo ethereum viene richiesto alla sorgente
+	prices, err := cache.GetPrices(context.Background(), []string{"bitcoin", "ethereum"}, "usd")
+	if err != nil || prices["bitcoin"] != 65000 || prices["ethereum"] != 3200 {
+		t.Fatalf("prezzi = %v, err = %v", prices, err)
+	}
+
+	// Un prezzo in un'altra valuta è una voce diversa della cache
+	source.Set("bitcoin", "eur", 60000)
+	if price, err := cache.GetPrice(context.Background(), "bitcoin", "eur"); err != nil || price != 60000 {
+		t.Fatalf("prezzo in eur = %v, err = %v", price, err)
+	}
+	if n := source.Calls(); n != 3 {
+		t.Fatalf("richieste alla sorgente = %d, attese 3", n)
+	}
+}
+
+func TestCacheQuoteAge(t *testing.T) {
+	source := pricingtest.New("fake").Set("bitcoin", "usd", 65000)
+	cache := pricing.NewCache(source, time.Minute)
+
+	first, err := cache.GetQuote(context.Background(), "bitcoin", "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Un prezzo servito dalla cache mantiene l'istante in cui è stato ottenuto
+	second, err := cache.GetQuote(context.Background(), "bitcoin", "usd")
+	if err != nil || !second.AsOf.Equal(first.AsOf) || second.Age() < 10*time.Millisecond {
+		t.Fatalf("quotazione = %+v, err = %v, attesa quella del %v", second, err, first.AsOf)
+	}
+
+	if _, err := cache.GetQuote(context.Background(), "unknown", "usd"); !errors.Is(err, pricing.ErrPriceNotFound) {
+		t.Fatalf("errore = %v, atteso ErrPriceNotFound", err)
+	}
+}
+
+func TestCacheMergesConcurrentRequests(t *testing.T) {
+	source := pricingtest.New("fake").Set("bitcoin", "usd", 65000)
+	cache := pricing.NewCache(source, time.Minute)
+	release := source.Hold()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if price, err := cache.GetPrice(context.Background(), "bitcoin", "usd"); err != nil || price != 65000 {
+				t.Errorf("prezzo = %v, err = %v", price, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	release()
+	wg.Wait()
+
+	if n := source.Calls(); n != 1 {
+		t.Fatalf("richieste alla sorgente = %d, attesa 1", n)
+	}
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	source := pricingtest.New("fake").Set("bitcoin", "usd", 65000)
+	source.Fail(errors.New("sorgente non disponibile"))
+	cache := pricing.NewCache(source, time.Minute)
+
+	if _, err := cache.GetPrice(context.Background(), "bitcoin", "usd"); err == nil {
+		t.Fatal("errore atteso")
+	}
+
+	source.Fail(nil)
+	if price, err := cache.GetPrice(context.Background(), "bitcoin", "usd"); err != nil || price != 65000 {
+		t.Fatalf("prezzo = %v, err = %v", price, err)
+	}
+}
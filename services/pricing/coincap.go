@@ -0,0 +1,86 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultCoinCapBaseURL è l'URL base dell'API di CoinCap
+const DefaultCoinCapBaseURL = "https://api.coincap.io/v2"
+
+// CoinCap implementa PriceProvider usando l'endpoint /assets di CoinCap,
+// i cui ID coincidono con quelli di CoinGecko per la maggior parte delle criptovalute.
+// CoinCap quota solo in USD: per le altre valute non restituisce prezzi.
+type CoinCap struct {
+	client *resty.Client
+}
+
+// NewCoinCap crea un client CoinCap; se baseURL è vuoto usa l'API pubblica
+func NewCoinCap(apiKey, baseURL string) *CoinCap {
+	if baseURL == "" {
+		baseURL = DefaultCoinCapBaseURL
+	}
+
+	client := resty.New().
+		SetBaseURL(strings.TrimRight(baseURL, "/")).
+		SetTimeout(15 * time.Second)
+	if apiKey != "" {
+		client.SetAuthToken(apiKey)
+	}
+
+	return &CoinCap{client: client}
+}
+
+// Name restituisce il nome della sorgente
+func (cc *CoinCap) Name() string {
+	return "coincap"
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta nella valuta indicata
+func (cc *CoinCap) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	return getSinglePrice(ctx, cc, coinID, currency)
+}
+
+// GetPrices restituisce i prezzi in USD di più criptovalute con una sola richiesta
+func (cc *CoinCap) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(coinIDs))
+	if len(coinIDs) == 0 || currency != "usd" {
+		return prices, nil
+	}
+
+	var result struct {
+		Data []struct {
+			ID       string `json:"id"`
+			PriceUSD string `json:"priceUsd"`
+		} `json:"data"`
+	}
+
+	resp, err := cc.client.R().
+		SetContext(ctx).
+		SetQueryParam("ids", strings.Join(coinIDs, ",")).
+		SetResult(&result).
+		Get("/assets")
+	if err != nil {
+		return nil, fmt.Errorf("errore nella richiesta a CoinCap: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("risposta non valida da CoinCap: %s", resp.Status())
+	}
+
+	for _, asset := range result.Data {
+		price, err := strconv.ParseFloat(asset.PriceUSD, 64)
+		if err != nil {
+			log.Printf("[CoinCap] Prezzo non valido per %s: %q", asset.ID, asset.PriceUSD)
+			continue
+		}
+		prices[asset.ID] = price
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,74 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newCoinCapServer avvia un finto server CoinCap che restituisce i prezzi degli asset indicati
+func newCoinCapServer(t *testing.T, known map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/assets" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		data := []map[string]string{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			if price, ok := known[id]; ok {
+				data = append(data, map[string]string{"id": id, "priceUsd": price})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestCoinCapGetPrices(t *testing.T) {
+	server, _ := newCoinCapServer(t, map[string]string{"bitcoin": "65000.25", "ethereum": "3200", "solana": "n/a"})
+
+	prices, err := NewCoinCap("test-key", server.URL).GetPrices(context.Background(), []string{"bitcoin", "ethereum", "solana", "unknown-coin"}, "usd")
+	if err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	// I prezzi non numerici vengono ignorati
+	if len(prices) != 2 || prices["bitcoin"] != 65000.25 || prices["ethereum"] != 3200 {
+		t.Fatalf("prezzi inattesi: %v", prices)
+	}
+}
+
+func TestCoinCapGetPricesServerError(t *testing.T) {
+	server, _ := newCoinCapServer(t, nil)
+
+	// Senza chiave API il finto server risponde 401
+	_, err := NewCoinCap("", server.URL).GetPrices(context.Background(), []string{"bitcoin"}, "usd")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("errore atteso per risposta 401, ottenuto %v", err)
+	}
+}
+
+func TestCoinCapGetPricesOnlyInUSD(t *testing.T) {
+	server, requests := newCoinCapServer(t, map[string]string{"bitcoin": "65000"})
+
+	// CoinCap quota solo in USD: per le altre valute non viene fatta alcuna richiesta
+	prices, err := NewCoinCap("test-key", server.URL).GetPrices(context.Background(), []string{"bitcoin"}, "eur")
+	if err != nil || len(prices) != 0 || requests.Load() != 0 {
+		t.Fatalf("prezzi = %v, err = %v, richieste = %d", prices, err, requests.Load())
+	}
+}
@@ -0,0 +1,285 @@
+package pricing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// URL base dell'API di CoinGecko per le chiavi demo (o senza chiave) e per le chiavi pro
+const (
+	DefaultCoinGeckoBaseURL = "https://api.coingecko.com/api/v3"
+	CoinGeckoProBaseURL     = "https://pro-api.coingecko.com/api/v3"
+)
+
+// maxIDsPerRequest è il numero massimo di ID inviati nel parametro "ids" di una singola richiesta,
+// per restare entro i limiti di lunghezza dell'URL accettati da CoinGecko
+const maxIDsPerRequest = 100
+
+// Budget di richieste al minuto predefiniti per ciascun piano CoinGecko
+const (
+	publicRequestsPerMinute = 5
+	demoRequestsPerMinute   = 30
+	proRequestsPerMinute    = 500
+)
+
+// Parametri predefiniti per i tentativi e per il circuit breaker
+const (
+	defaultMaxRetries       = 3
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 2 * time.Minute
+	retryBaseDelay          = time.Second
+	retryMaxDelay           = 30 * time.Second
+)
+
+// CoinGeckoConfig contiene la configurazione del client CoinGecko
+type CoinGeckoConfig struct {
+	APIKey            string
+	Pro               bool   // true per le chiavi del piano a pagamento
+	BaseURL           string // URL alternativo (es. server locale nei test); se vuoto dipende dal piano
+	RequestsPerMinute int    // budget lato client; se 0 dipende dal piano
+	MaxRetries        int    // tentativi aggiuntivi dopo un errore temporaneo; se 0 usa il default
+	BreakerThreshold  int    // fallimenti consecutivi prima di aprire il circuito; se 0 usa il default
+	BreakerCooldown   time.Duration
+}
+
+// CoinGecko implementa PriceProvider usando l'endpoint /simple/price di CoinGecko.
+// Il client è pensato per essere di lunga durata: rispetta un budget di richieste al minuto,
+// ritenta gli errori temporanei (onorando Retry-After per i 429) e apre un circuit breaker
+// dopo fallimenti ripetuti.
+type CoinGecko struct {
+	client     *resty.Client
+	apiKey     string
+	keyHeader  string
+	maxRetries int
+	limiter    *rateLimiter
+	breaker    *circuitBreaker
+}
+
+// NewCoinGecko crea un client CoinGecko di lunga durata
+func NewCoinGecko(cfg CoinGeckoConfig) *CoinGecko {
+	baseURL := cfg.BaseURL
+	keyHeader := "x-cg-demo-api-key"
+	requestsPerMinute := publicRequestsPerMinute
+
+	switch {
+	case cfg.Pro:
+		keyHeader = "x-cg-pro-api-key"
+		requestsPerMinute = proRequestsPerMinute
+		if baseURL == "" {
+			baseURL = CoinGeckoProBaseURL
+		}
+	case cfg.APIKey != "":
+		requestsPerMinute = demoRequestsPerMinute
+	}
+
+	if baseURL == "" {
+		baseURL = DefaultCoinGeckoBaseURL
+	}
+	if cfg.RequestsPerMinute > 0 {
+		requestsPerMinute = cfg.RequestsPerMinute
+	}
+
+	maxRetries := cmp.Or(cfg.MaxRetries, defaultMaxRetries)
+	threshold := cmp.Or(cfg.BreakerThreshold, defaultBreakerThreshold)
+	cooldown := cmp.Or(cfg.BreakerCooldown, defaultBreakerCooldown)
+
+	client := resty.New().
+		SetBaseURL(strings.TrimRight(baseURL, "/")).
+		SetTimeout(15 * time.Second)
+
+	return &CoinGecko{
+		client:     client,
+		apiKey:     cfg.APIKey,
+		keyHeader:  keyHeader,
+		maxRetries: maxRetries,
+		limiter:    newRateLimiter(requestsPerMinute),
+		breaker:    newCircuitBreaker(threshold, cooldown),
+	}
+}
+
+// NewCoinGeckoFromEnv crea un client CoinGecko leggendo COINGECKO_API_KEY, COINGECKO_API_PLAN
+// ("demo" o "pro"), COINGECKO_RATE_LIMIT (richieste al minuto) e COINGECKO_BASE_URL
+func NewCoinGeckoFromEnv() *CoinGecko {
+	cfg := CoinGeckoConfig{
+		APIKey:  os.Getenv("COINGECKO_API_KEY"),
+		Pro:     strings.EqualFold(os.Getenv("COINGECKO_API_PLAN"), "pro"),
+		BaseURL: os.Getenv("COINGECKO_BASE_URL"),
+	}
+
+	if cfg.APIKey == "" {
+		log.Println("[CoinGecko] AVVISO: COINGECKO_API_KEY non impostata. L'API potrebbe avere limitazioni.")
+	} else {
+		log.Printf("[CoinGecko] Chiave API trovata (lunghezza: %d caratteri, piano pro: %v)", len(cfg.APIKey), cfg.Pro)
+	}
+
+	if value := os.Getenv("COINGECKO_RATE_LIMIT"); value != "" {
+		if rpm, err := strconv.Atoi(value); err == nil {
+			cfg.RequestsPerMinute = rpm
+		} else {
+			log.Printf("[CoinGecko] Valore non valido per COINGECKO_RATE_LIMIT (%q), uso il default del piano", value)
+		}
+	}
+
+	return NewCoinGecko(cfg)
+}
+
+// Name restituisce il nome della sorgente
+func (cg *CoinGecko) Name() string {
+	return "coingecko"
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta nella valuta indicata
+func (cg *CoinGecko) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	return getSinglePrice(ctx, cg, coinID, currency)
+}
+
+// GetPrices restituisce i prezzi di più criptovalute nella valuta indicata
+func (cg *CoinGecko) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	quotes, err := cg.GetQuotes(ctx, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return pricesFromQuotes(quotes), nil
+}
+
+// GetQuote restituisce prezzo e variazione nelle 24 ore di una singola criptovaluta
+func (cg *CoinGecko) GetQuote(ctx context.Context, coinID, currency string) (Quote, error) {
+	return getSingleQuote(ctx, cg, coinID, currency)
+}
+
+// GetQuotes restituisce prezzi e variazioni nelle 24 ore di più criptovalute, usando il minor
+// numero possibile di richieste a /simple/price (gli ID vengono suddivisi in blocchi)
+func (cg *CoinGecko) GetQuotes(ctx context.Context, coinIDs []string, currency string) (map[string]Quote, error) {
+	quotes := make(map[string]Quote, len(coinIDs))
+
+	for start := 0; start < len(coinIDs); start += maxIDsPerRequest {
+		end := min(start+maxIDsPerRequest, len(coinIDs))
+
+		if err := cg.fetchQuotes(ctx, coinIDs[start:end], currency, quotes); err != nil {
+			return nil, err
+		}
+	}
+
+	return quotes, nil
+}
+
+// fetchQuotes esegue una singola richiesta a /simple/price e aggiunge le quotazioni ottenute a quotes
+func (cg *CoinGecko) fetchQuotes(ctx context.Context, coinIDs []string, currency string, quotes map[string]Quote) error {
+	var result map[string]map[string]*float64
+
+	params := map[string]string{
+		"ids":                 strings.Join(coinIDs, ","),
+		"vs_currencies":       currency,
+		"include_24hr_change": "true",
+	}
+	if err := cg.get(ctx, "/simple/price", params, &result); err != nil {
+		return err
+	}
+
+	asOf := time.Now()
+	for id, values := range result {
+		price := values[currency]
+		if price == nil {
+			continue
+		}
+		quotes[id] = Quote{
+			CoinID:    id,
+			Currency:  currency,
+			Price:     *price,
+			Change24h: values[currency+"_24h_change"],
+			AsOf:      asOf,
+		}
+	}
+
+	return nil
+}
+
+// get esegue una richiesta GET rispettando il budget di richieste, ritentando gli errori
+// temporanei e aggiornando il circuit breaker. Il corpo JSON viene decodificato in result.
+// Anche una richiesta interrotta dal contesto (es. un'attesa oltre la scadenza) conta come fallimento.
+func (cg *CoinGecko) get(ctx context.Context, path string, params map[string]string, result any) error {
+	if err := cg.breaker.Allow(); err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= cg.maxRetries; attempt++ {
+		if err := cg.limiter.Wait(ctx); err != nil {
+			return cg.fail(err)
+		}
+
+		request := cg.client.R().
+			SetContext(ctx).
+			SetQueryParams(params).
+			SetResult(result)
+		if cg.apiKey != "" {
+			request.SetHeader(cg.keyHeader, cg.apiKey)
+		}
+
+		startTime := time.Now()
+		resp, err := request.Get(path)
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return cg.fail(ctx.Err())
+			}
+			lastErr = fmt.Errorf("errore nella richiesta a CoinGecko: %w", err)
+			wait = backoff(attempt, retryBaseDelay, retryMaxDelay)
+
+		case resp.StatusCode() == http.StatusTooManyRequests:
+			lastErr = fmt.Errorf("limite di richieste CoinGecko superato: %s", resp.Status())
+			var ok bool
+			if wait, ok = retryAfter(resp.Header().Get("Retry-After")); !ok {
+				wait = backoff(attempt, retryBaseDelay, retryMaxDelay)
+			}
+
+		case resp.StatusCode() >= http.StatusInternalServerError:
+			lastErr = fmt.Errorf("risposta non valida da CoinGecko: %s", resp.Status())
+			wait = backoff(attempt, retryBaseDelay, retryMaxDelay)
+
+		case resp.IsError():
+			// Gli altri errori 4xx non sono temporanei: inutile ritentare. Non dicono nulla
+			// sulla disponibilità della sorgente, quindi non chiudono il circuito.
+			log.Printf("[CoinGecko] Richiesta %s rifiutata: %s", path, resp.Status())
+			cg.breaker.Release()
+			return fmt.Errorf("risposta non valida da CoinGecko: %s", resp.Status())
+
+		default:
+			log.Printf("[CoinGecko] %s completata in %v", path, time.Since(startTime).Round(time.Millisecond))
+			cg.breaker.Success()
+			return nil
+		}
+
+		if attempt == cg.maxRetries {
+			break
+		}
+
+		log.Printf("[CoinGecko] Tentativo %d/%d fallito (%v), nuovo tentativo tra %v",
+			attempt+1, cg.maxRetries+1, lastErr, wait.Round(time.Millisecond))
+		if err := sleepContext(ctx, wait); err != nil {
+			return cg.fail(err)
+		}
+	}
+
+	return cg.fail(lastErr)
+}
+
+// fail registra un fallimento nel circuit breaker e restituisce err
+func (cg *CoinGecko) fail(err error) error {
+	if cg.breaker.Failure() {
+		log.Printf("[CoinGecko] ⚠️ Troppi errori consecutivi, circuit breaker aperto")
+	}
+	return err
+}
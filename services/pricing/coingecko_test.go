@@ -0,0 +1,220 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCoinGeckoServer avvia un server locale che risponde come l'endpoint /simple/price di CoinGecko
+func newCoinGeckoServer(t *testing.T, handler http.HandlerFunc) *CoinGecko {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewCoinGecko(CoinGeckoConfig{
+		APIKey:            "test-key",
+		BaseURL:           server.URL,
+		RequestsPerMinute: 60000,
+		BreakerThreshold:  2,
+	})
+}
+
+func TestCoinGeckoGetPrices(t *testing.T) {
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/price" || r.URL.Query().Get("vs_currencies") != "usd" {
+			t.Errorf("richiesta inattesa: %s", r.URL)
+		}
+		if r.Header.Get("x-cg-demo-api-key") != "test-key" {
+			t.Errorf("chiave API mancante")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"bitcoin":{"usd":65000.5},"ethereum":{"usd":3200}}`))
+	})
+
+	prices, err := cg.GetPrices(context.Background(), []string{"bitcoin", "ethereum", "unknown"}, "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 2 || prices["bitcoin"] != 65000.5 || prices["ethereum"] != 3200 {
+		t.Fatalf("prezzi inattesi: %v", prices)
+	}
+
+	if _, err := cg.GetPrice(context.Background(), "unknown", "usd"); !errors.Is(err, ErrPriceNotFound) {
+		t.Fatalf("errore = %v, atteso ErrPriceNotFound", err)
+	}
+}
+
+func TestCoinGeckoGetPricesInCurrency(t *testing.T) {
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if currency := r.URL.Query().Get("vs_currencies"); currency != "eur" {
+			t.Errorf("valuta richiesta = %q, attesa eur", currency)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"bitcoin":{"eur":60000}}`))
+	})
+
+	price, err := cg.GetPrice(context.Background(), "bitcoin", "eur")
+	if err != nil || price != 60000 {
+		t.Fatalf("prezzo = %v, err = %v", price, err)
+	}
+}
+
+func TestCoinGeckoGetQuotesWithChange24h(t *testing.T) {
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_24hr_change") != "true" {
+			t.Error("variazione nelle 24 ore non richiesta")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"bitcoin":{"usd":65000,"usd_24h_change":2.5},"tether":{"usd":1}}`))
+	})
+
+	quotes, err := cg.GetQuotes(context.Background(), []string{"bitcoin", "tether"}, "usd")
+	if err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	if btc := quotes["bitcoin"]; btc.Price != 65000 || btc.Change24h == nil || *btc.Change24h != 2.5 || btc.AsOf.IsZero() {
+		t.Fatalf("quotazione di bitcoin inattesa: %+v", btc)
+	}
+	if usdt := quotes["tether"]; usdt.Price != 1 || usdt.Change24h != nil {
+		t.Fatalf("quotazione di tether inattesa: %+v", usdt)
+	}
+}
+
+func TestCoinGeckoSplitsLongRequests(t *testing.T) {
+	requests := 0
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		if len(ids) > maxIDsPerRequest {
+			t.Errorf("richiesta con %d ID, massimo %d", len(ids), maxIDsPerRequest)
+		}
+
+		body := make([]string, 0, len(ids))
+		for _, id := range ids {
+			body = append(body, fmt.Sprintf(`%q:{"usd":1}`, id))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{" + strings.Join(body, ",") + "}"))
+	})
+
+	coinIDs := make([]string, 2*maxIDsPerRequest+1)
+	for i := range coinIDs {
+		coinIDs[i] = fmt.Sprintf("coin-%d", i)
+	}
+
+	prices, err := cg.GetPrices(context.Background(), coinIDs, "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != len(coinIDs) || requests != 3 {
+		t.Fatalf("ottenuti %d prezzi con %d richieste, attesi %d con 3", len(prices), requests, len(coinIDs))
+	}
+}
+
+func TestCoinGeckoErrorStatus(t *testing.T) {
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	})
+
+	if _, err := cg.GetPrices(context.Background(), []string{"bitcoin"}, "usd"); err == nil {
+		t.Fatal("errore atteso con una risposta 404")
+	}
+}
+
+func TestCoinGeckoRetriesRateLimitedRequests(t *testing.T) {
+	var requests atomic.Int32
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Le prime due richieste superano il limite, la terza va a buon fine
+		if requests.Add(1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"bitcoin":{"usd":65000}}`))
+	})
+
+	price, err := cg.GetPrice(context.Background(), "bitcoin", "usd")
+	if err != nil || price != 65000 {
+		t.Fatalf("prezzo = %v, err = %v", price, err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("richieste = %d, attese 3", n)
+	}
+}
+
+func TestCoinGeckoOpensCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// Ogni chiamata esaurisce i tentativi; alla seconda chiamata fallita il circuito si apre
+	for range 2 {
+		if _, err := cg.GetPrices(context.Background(), []string{"bitcoin"}, "usd"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("errore della sorgente atteso, ottenuto %v", err)
+		}
+	}
+
+	requests.Store(0)
+	if _, err := cg.GetPrices(context.Background(), []string{"bitcoin"}, "usd"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("errore = %v, atteso ErrCircuitOpen", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("richieste con il circuito aperto = %d, attese 0", n)
+	}
+}
+
+func TestCoinGeckoClientErrorKeepsFailures(t *testing.T) {
+	var notFound atomic.Bool
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if notFound.Load() {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// Un 404 tra due chiamate fallite non azzera il conteggio: il circuito si apre comunque
+	for _, status404 := range []bool{false, true, false} {
+		notFound.Store(status404)
+		if _, err := cg.GetPrices(context.Background(), []string{"bitcoin"}, "usd"); err == nil {
+			t.Fatal("errore atteso")
+		}
+	}
+
+	if _, err := cg.GetPrices(context.Background(), []string{"bitcoin"}, "usd"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("errore = %v, atteso ErrCircuitOpen", err)
+	}
+}
+
+func TestCoinGeckoCancelledWaitCountsAsFailure(t *testing.T) {
+	cg := newCoinGeckoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// L'attesa richiesta da Retry-After supera la scadenza del contesto
+	for range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := cg.GetPrices(ctx, []string{"bitcoin"}, "usd")
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("errore = %v, atteso context.DeadlineExceeded", err)
+		}
+	}
+
+	if _, err := cg.GetPrices(context.Background(), []string{"bitcoin"}, "usd"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("errore = %v, atteso ErrCircuitOpen", err)
+	}
+}
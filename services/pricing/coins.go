@@ -0,0 +1,124 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+)
+
+// ErrCoinListUnsupported indica che nessuna sorgente configurata fornisce l'elenco delle criptovalute
+var ErrCoinListUnsupported = errors.New("elenco delle criptovalute non supportato dalla sorgente")
+
+// marketCapPages è il numero di pagine di /coins/markets (250 criptovalute ciascuna) usate
+// per ordinare per capitalizzazione le criptovalute con lo stesso simbolo o nome
+const marketCapPages = 4
+
+// CoinInfo descrive una criptovaluta elencata da una sorgente
+type CoinInfo struct {
+	ID            string
+	Symbol        string
+	Name          string
+	MarketCap     float64 // capitalizzazione in USD (0 se non nota)
+	MarketCapRank int     // posizione per capitalizzazione (0 se non nota)
+}
+
+// CoinListProvider è implementato dalle sorgenti in grado di elencare le criptovalute supportate
+type CoinListProvider interface {
+	// ListCoins restituisce tutte le criptovalute note alla sorgente
+	ListCoins(ctx context.Context) ([]CoinInfo, error)
+}
+
+// ListCoins ottiene l'elenco delle criptovalute da qualsiasi PriceProvider, se lo supporta
+func ListCoins(ctx context.Context, prices PriceProvider) ([]CoinInfo, error) {
+	if lp, ok := prices.(CoinListProvider); ok {
+		return lp.ListCoins(ctx)
+	}
+	return nil, ErrCoinListUnsupported
+}
+
+// listCoinsFromSources interroga in ordine le sorgenti che forniscono l'elenco delle criptovalute
+func listCoinsFromSources(ctx context.Context, sources []PriceProvider) ([]CoinInfo, error) {
+	lastErr := ErrCoinListUnsupported
+	for _, source := range sources {
+		coins, err := ListCoins(ctx, source)
+		if err == nil {
+			return coins, nil
+		}
+		if !errors.Is(err, ErrCoinListUnsupported) {
+			log.Printf("[Prezzi] Elenco delle criptovalute non disponibile da %s: %v", sourceName(source), err)
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
+
+// ListCoins restituisce l'elenco delle criptovalute dalla sorgente sottostante (non viene messo in cache)
+func (c *Cache) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	return ListCoins(ctx, c.source)
+}
+
+// ListCoins restituisce l'elenco delle criptovalute dalla prima sorgente che lo fornisce
+func (f *Fallback) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	return listCoinsFromSources(ctx, f.sources)
+}
+
+// ListCoins restituisce l'elenco delle criptovalute dalla prima sorgente che lo fornisce
+func (m *Median) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	return listCoinsFromSources(ctx, m.sources)
+}
+
+// ListCoins restituisce le criptovalute di /coins/list, completate con la capitalizzazione
+// delle prime marketCapPages pagine di /coins/markets. Se la capitalizzazione non è
+// disponibile l'elenco viene restituito comunque.
+func (cg *CoinGecko) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	var list []struct {
+		ID     string `json:"id"`
+		Symbol string `json:"symbol"`
+		Name   string `json:"name"`
+	}
+	if err := cg.get(ctx, "/coins/list", nil, &list); err != nil {
+		return nil, err
+	}
+
+	type marketData struct {
+		MarketCap     float64 `json:"market_cap"`
+		MarketCapRank int     `json:"market_cap_rank"`
+	}
+	markets := make(map[string]marketData)
+	for page := 1; page <= marketCapPages; page++ {
+		var result []struct {
+			ID string `json:"id"`
+			marketData
+		}
+		params := map[string]string{
+			"vs_currency": DefaultCurrency,
+			"order":       "market_cap_desc",
+			"per_page":    "250",
+			"page":        strconv.Itoa(page),
+		}
+		if err := cg.get(ctx, "/coins/markets", params, &result); err != nil {
+			log.Printf("[CoinGecko] Capitalizzazioni non disponibili (pagina %d): %v", page, err)
+			break
+		}
+		for _, market := range result {
+			markets[market.ID] = market.marketData
+		}
+		if len(result) < 250 {
+			break
+		}
+	}
+
+	coins := make([]CoinInfo, 0, len(list))
+	for _, coin := range list {
+		market := markets[coin.ID]
+		coins = append(coins, CoinInfo{
+			ID:            coin.ID,
+			Symbol:        coin.Symbol,
+			Name:          coin.Name,
+			MarketCap:     market.MarketCap,
+			MarketCapRank: market.MarketCapRank,
+		})
+	}
+	return coins, nil
+}
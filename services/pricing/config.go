@@ -0,0 +1,51 @@
+package pricing
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NewProviderFromEnv costruisce la sorgente dei prezzi descritta dalle variabili d'ambiente:
+//   - PRICE_SOURCES: elenco ordinato di sorgenti (coingecko, binance, kraken, coincap), default "coingecko"
+//   - PRICE_AGGREGATION: "fallback" (default) oppure "median"
+//   - PRICE_MAX_DEVIATION: scostamento massimo dalla mediana prima di scartare un prezzo (es. 0.02)
+func NewProviderFromEnv() PriceProvider {
+	names := os.Getenv("PRICE_SOURCES")
+	if names == "" {
+		names = "coingecko"
+	}
+
+	var sources []PriceProvider
+	for _, name := range strings.Split(names, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "coingecko":
+			sources = append(sources, NewCoinGeckoFromEnv())
+		case "binance":
+			sources = append(sources, NewBinance(os.Getenv("BINANCE_BASE_URL")))
+		case "kraken":
+			sources = append(sources, NewKraken(os.Getenv("KRAKEN_BASE_URL")))
+		case "coincap":
+			sources = append(sources, NewCoinCap(os.Getenv("COINCAP_API_KEY"), os.Getenv("COINCAP_BASE_URL")))
+		default:
+			log.Printf("[Prezzi] Sorgente sconosciuta in PRICE_SOURCES: %q, la ignoro", name)
+		}
+	}
+
+	if len(sources) == 0 {
+		sources = append(sources, NewCoinGeckoFromEnv())
+	}
+	if len(sources) == 1 {
+		return sources[0]
+	}
+
+	if strings.EqualFold(os.Getenv("PRICE_AGGREGATION"), "median") {
+		maxDeviation, _ := strconv.ParseFloat(os.Getenv("PRICE_MAX_DEVIATION"), 64)
+		log.Printf("[Prezzi] Uso la mediana di %d sorgenti", len(sources))
+		return NewMedian(maxDeviation, sources...)
+	}
+
+	log.Printf("[Prezzi] Uso %d sorgenti in fallback", len(sources))
+	return NewFallback(sources...)
+}
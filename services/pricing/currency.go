@@ -0,0 +1,68 @@
+package pricing
+
+import (
+	"crypto-tracker/i18n"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultCurrency è la valuta usata quando non ne viene indicata una
+const DefaultCurrency = "usd"
+
+// SupportedCurrencies elenca le valute di quotazione accettate da API e bot
+var SupportedCurrencies = []string{"usd", "eur", "gbp", "chf", "jpy", "cad", "aud", "btc", "eth"}
+
+// currencySymbols contiene i simboli usati per formattare gli importi
+var currencySymbols = map[string]string{
+	"usd": "$",
+	"eur": "€",
+	"gbp": "£",
+	"jpy": "¥",
+	"btc": "₿",
+	"eth": "Ξ",
+}
+
+// cryptoCurrencies sono le valute di quotazione che richiedono più cifre decimali
+var cryptoCurrencies = map[string]bool{
+	"btc": true,
+	"eth": true,
+}
+
+// NormalizeCurrency porta il codice valuta in minuscolo, usando DefaultCurrency se vuoto
+func NormalizeCurrency(currency string) string {
+	currency = strings.ToLower(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// IsSupportedCurrency indica se la valuta (già normalizzata) è tra quelle supportate
+func IsSupportedCurrency(currency string) bool {
+	return slices.Contains(SupportedCurrencies, currency)
+}
+
+// ParseCurrency normalizza e valida un codice valuta
+func ParseCurrency(currency string) (string, error) {
+	currency = NormalizeCurrency(currency)
+	if !IsSupportedCurrency(currency) {
+		return "", i18n.Errorf("validation.unsupported_currency", currency, strings.Join(SupportedCurrencies, ", "))
+	}
+	return currency, nil
+}
+
+// FormatAmount formatta un importo nella valuta indicata (es. "$68123.45", "₿0.01234567")
+func FormatAmount(amount float64, currency string) string {
+	currency = NormalizeCurrency(currency)
+
+	decimals := 2
+	if cryptoCurrencies[currency] {
+		decimals = 8
+	}
+
+	if symbol, ok := currencySymbols[currency]; ok {
+		return fmt.Sprintf("%s%.*f", symbol, decimals, amount)
+	}
+	return fmt.Sprintf("%.*f %s", decimals, amount, strings.ToUpper(currency))
+}
@@ -0,0 +1,92 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrHistoryUnsupported indica che nessuna sorgente configurata fornisce prezzi storici
+var ErrHistoryUnsupported = errors.New("storico prezzi non supportato dalla sorgente")
+
+// PricePoint è un prezzo storico in un determinato istante
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// HistoryProvider è implementato dalle sorgenti in grado di fornire prezzi storici
+type HistoryProvider interface {
+	// GetHistory restituisce i prezzi di una criptovaluta nel periodo [from, to], in ordine cronologico
+	GetHistory(ctx context.Context, coinID, currency string, from, to time.Time) ([]PricePoint, error)
+}
+
+// GetHistory ottiene i prezzi storici da qualsiasi PriceProvider, se li supporta
+func GetHistory(ctx context.Context, prices PriceProvider, coinID, currency string, from, to time.Time) ([]PricePoint, error) {
+	if hp, ok := prices.(HistoryProvider); ok {
+		return hp.GetHistory(ctx, coinID, currency, from, to)
+	}
+	return nil, ErrHistoryUnsupported
+}
+
+// getHistoryFromSources interroga in ordine le sorgenti che forniscono prezzi storici
+func getHistoryFromSources(ctx context.Context, sources []PriceProvider, coinID, currency string, from, to time.Time) ([]PricePoint, error) {
+	lastErr := ErrHistoryUnsupported
+	for _, source := range sources {
+		points, err := GetHistory(ctx, source, coinID, currency, from, to)
+		if err == nil {
+			return points, nil
+		}
+		if !errors.Is(err, ErrHistoryUnsupported) {
+			log.Printf("[Prezzi] Storico non disponibile da %s: %v", sourceName(source), err)
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
+
+// GetHistory restituisce i prezzi storici dalla sorgente sottostante (non vengono messi in cache)
+func (c *Cache) GetHistory(ctx context.Context, coinID, currency string, from, to time.Time) ([]PricePoint, error) {
+	return GetHistory(ctx, c.source, coinID, currency, from, to)
+}
+
+// GetHistory restituisce i prezzi storici dalla prima sorgente che li fornisce
+func (f *Fallback) GetHistory(ctx context.Context, coinID, currency string, from, to time.Time) ([]PricePoint, error) {
+	return getHistoryFromSources(ctx, f.sources, coinID, currency, from, to)
+}
+
+// GetHistory restituisce i prezzi storici dalla prima sorgente che li fornisce
+func (m *Median) GetHistory(ctx context.Context, coinID, currency string, from, to time.Time) ([]PricePoint, error) {
+	return getHistoryFromSources(ctx, m.sources, coinID, currency, from, to)
+}
+
+// GetHistory restituisce i prezzi storici usando l'endpoint /coins/{id}/market_chart/range.
+// CoinGecko sceglie la granularità in base al periodo (5 minuti fino a un giorno, poi oraria).
+func (cg *CoinGecko) GetHistory(ctx context.Context, coinID, currency string, from, to time.Time) ([]PricePoint, error) {
+	var result struct {
+		Prices [][2]float64 `json:"prices"` // coppie [timestamp in millisecondi, prezzo]
+	}
+
+	params := map[string]string{
+		"vs_currency": currency,
+		"from":        strconv.FormatInt(from.Unix(), 10),
+		"to":          strconv.FormatInt(to.Unix(), 10),
+	}
+	if err := cg.get(ctx, "/coins/"+url.PathEscape(coinID)+"/market_chart/range", params, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Prices) == 0 {
+		return nil, fmt.Errorf("%w per %s in %s (storico)", ErrPriceNotFound, coinID, currency)
+	}
+
+	points := make([]PricePoint, 0, len(result.Prices))
+	for _, p := range result.Prices {
+		points = append(points, PricePoint{Time: time.UnixMilli(int64(p[0])).UTC(), Price: p[1]})
+	}
+	return points, nil
+}
@@ -0,0 +1,196 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultKrakenBaseURL è l'URL base dell'API pubblica di Kraken
+const DefaultKrakenBaseURL = "https://api.kraken.com"
+
+// krakenAssets contiene i simboli che su Kraken hanno un nome diverso da quello comune
+var krakenAssets = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+// krakenQuotes associa le valute supportate al nome dell'asset di quotazione su Kraken
+var krakenQuotes = map[string]string{
+	"usd": "USD",
+	"eur": "EUR",
+	"gbp": "GBP",
+	"chf": "CHF",
+	"jpy": "JPY",
+	"cad": "CAD",
+	"aud": "AUD",
+	"btc": "XBT",
+}
+
+// errKrakenUnknownPair indica che Kraken non riconosce una delle coppie richieste
+var errKrakenUnknownPair = errors.New("coppia sconosciuta a Kraken")
+
+// krakenTicker è la quotazione di una coppia restituita da /0/public/Ticker
+type krakenTicker struct {
+	// C contiene [prezzo dell'ultimo scambio, volume]
+	C []string `json:"c"`
+}
+
+// Kraken implementa PriceProvider usando l'endpoint /0/public/Ticker
+type Kraken struct {
+	client  *resty.Client
+	invalid pairSet // Coppie che Kraken non riconosce
+}
+
+// NewKraken crea un client Kraken; se baseURL è vuoto usa l'API pubblica
+func NewKraken(baseURL string) *Kraken {
+	if baseURL == "" {
+		baseURL = DefaultKrakenBaseURL
+	}
+
+	return &Kraken{
+		client: resty.New().
+			SetBaseURL(strings.TrimRight(baseURL, "/")).
+			SetTimeout(15 * time.Second),
+	}
+}
+
+// Name restituisce il nome della sorgente
+func (k *Kraken) Name() string {
+	return "kraken"
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta nella valuta indicata
+func (k *Kraken) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	return getSinglePrice(ctx, k, coinID, currency)
+}
+
+// GetPrices restituisce i prezzi delle criptovalute supportate con una sola richiesta
+func (k *Kraken) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(coinIDs))
+
+	quote, ok := krakenQuotes[currency]
+	if !ok {
+		return prices, nil
+	}
+
+	// Kraken risponde con nomi di coppia propri (es. "XXBTZUSD" per "XBTUSD"),
+	// quindi teniamo traccia dell'asset base di ogni coppia richiesta
+	assets := make(map[string]string, len(coinIDs))
+	pairs := make([]string, 0, len(coinIDs))
+	for _, id := range coinIDs {
+		symbol, ok := tickerSymbol(id)
+		if !ok {
+			continue
+		}
+		if alias, ok := krakenAssets[symbol]; ok {
+			symbol = alias
+		}
+		if symbol == quote || k.invalid.has(symbol+quote) {
+			continue
+		}
+		if _, dup := assets[symbol]; !dup {
+			assets[symbol] = id
+			pairs = append(pairs, symbol+quote)
+		}
+	}
+
+	if len(pairs) == 0 {
+		return prices, nil
+	}
+
+	tickers, err := k.tickers(ctx, pairs)
+	switch {
+	case errors.Is(err, errKrakenUnknownPair):
+		// Una sola coppia sconosciuta fa fallire l'intera richiesta: le coppie vengono richieste
+		// una alla volta per individuare quelle non valide
+		for _, pair := range pairs {
+			single, err := k.tickers(ctx, []string{pair})
+			if errors.Is(err, errKrakenUnknownPair) {
+				log.Printf("[Kraken] Coppia %s non disponibile, esclusa dalle prossime richieste", pair)
+				k.invalid.add(pair)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Con una sola coppia richiesta il nome restituito non va interpretato
+			for pairName, ticker := range single {
+				k.setPrice(prices, assets[strings.TrimSuffix(pair, quote)], pairName, ticker)
+			}
+		}
+	case err != nil:
+		return nil, err
+	default:
+		for pairName, ticker := range tickers {
+			if id, ok := krakenCoinID(pairName, quote, assets); ok {
+				k.setPrice(prices, id, pairName, ticker)
+			}
+		}
+	}
+
+	return prices, nil
+}
+
+// tickers richiede le quotazioni delle coppie indicate, indicizzate per il nome usato da Kraken
+func (k *Kraken) tickers(ctx context.Context, pairs []string) (map[string]krakenTicker, error) {
+	var result struct {
+		Error  []string                `json:"error"`
+		Result map[string]krakenTicker `json:"result"`
+	}
+
+	resp, err := k.client.R().
+		SetContext(ctx).
+		SetQueryParam("pair", strings.Join(pairs, ",")).
+		SetResult(&result).
+		Get("/0/public/Ticker")
+	if err != nil {
+		return nil, fmt.Errorf("errore nella richiesta a Kraken: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("risposta non valida da Kraken: %s", resp.Status())
+	}
+	if len(result.Error) > 0 && len(result.Result) == 0 {
+		for _, msg := range result.Error {
+			if strings.Contains(msg, "Unknown asset pair") {
+				return nil, fmt.Errorf("%w: %s", errKrakenUnknownPair, strings.Join(pairs, ","))
+			}
+		}
+		return nil, fmt.Errorf("errore restituito da Kraken: %s", strings.Join(result.Error, ", "))
+	}
+
+	return result.Result, nil
+}
+
+// setPrice memorizza il prezzo dell'ultimo scambio di una coppia
+func (k *Kraken) setPrice(prices map[string]float64, id, pairName string, ticker krakenTicker) {
+	if id == "" || len(ticker.C) == 0 {
+		return
+	}
+	price, err := strconv.ParseFloat(ticker.C[0], 64)
+	if err != nil {
+		log.Printf("[Kraken] Prezzo non valido per %s: %q", pairName, ticker.C[0])
+		return
+	}
+	prices[id] = price
+}
+
+// krakenCoinID riconduce il nome di una coppia restituito da Kraken all'ID CoinGecko.
+// Le coppie storiche usano il prefisso X per le criptovalute e Z per le valute fiat (es. "XXBTZEUR"),
+// alcune coppie più recenti solo quello della valuta (es. "USDTZUSD").
+func krakenCoinID(pairName, quote string, assets map[string]string) (string, bool) {
+	for asset, id := range assets {
+		for _, base := range []string{asset, "X" + asset} {
+			if pairName == base+quote || pairName == base+"Z"+quote || pairName == base+"X"+quote {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,151 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// krakenPair è una coppia nota al finto server Kraken
+type krakenPair struct {
+	name  string // nome restituito da Kraken (es. "XXBTZUSD")
+	price string
+}
+
+// newKrakenServer avvia un finto server Kraken che conosce solo le coppie indicate e,
+// come quello reale, rifiuta l'intera richiesta se una coppia non esiste
+func newKrakenServer(t *testing.T, known map[string]krakenPair) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/0/public/Ticker" {
+			http.NotFound(w, r)
+			return
+		}
+
+		result := make(map[string]krakenTicker)
+		for _, pair := range strings.Split(r.URL.Query().Get("pair"), ",") {
+			p, ok := known[pair]
+			if !ok {
+				json.NewEncoder(w).Encode(map[string]any{"error": []string{"EQuery:Unknown asset pair"}})
+				return
+			}
+			result[p.name] = krakenTicker{C: []string{p.price, "0.1"}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"error": []string{}, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestKrakenGetPrices(t *testing.T) {
+	server, _ := newKrakenServer(t, map[string]krakenPair{
+		"XBTUSD":  {"XXBTZUSD", "65000.1"},
+		"ETHUSD":  {"XETHZUSD", "3200"},
+		"USDTUSD": {"USDTZUSD", "1.0002"},
+		"SOLUSD":  {"SOLUSD", "150"},
+		"XDGUSD":  {"XDGUSD", "0.12"},
+	})
+
+	prices, err := NewKraken(server.URL).GetPrices(context.Background(),
+		[]string{"bitcoin", "ethereum", "tether", "solana", "dogecoin"}, "usd")
+	if err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+
+	want := map[string]float64{"bitcoin": 65000.1, "ethereum": 3200, "tether": 1.0002, "solana": 150, "dogecoin": 0.12}
+	if len(prices) != len(want) {
+		t.Fatalf("prezzi = %v, attesi %v", prices, want)
+	}
+	for id, price := range want {
+		if prices[id] != price {
+			t.Errorf("prezzo di %s = %v, atteso %v", id, prices[id], price)
+		}
+	}
+}
+
+func TestKrakenGetPricesSkipsUnknownPairs(t *testing.T) {
+	server, requests := newKrakenServer(t, map[string]krakenPair{
+		"ETHXBT": {"XETHXXBT", "0.05"},
+	})
+	kraken := NewKraken(server.URL)
+	coinIDs := []string{"ethereum", "shiba-inu", "bitcoin"}
+
+	prices, err := kraken.GetPrices(context.Background(), coinIDs, "btc")
+	if err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	if len(prices) != 1 || prices["ethereum"] != 0.05 {
+		t.Fatalf("prezzi inattesi: %v", prices)
+	}
+
+	// La coppia sconosciuta viene esclusa: la richiesta successiva è di nuovo una sola
+	requests.Store(0)
+	if _, err := kraken.GetPrices(context.Background(), coinIDs, "btc"); err != nil {
+		t.Fatalf("errore inatteso: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("richieste = %d, attesa 1", n)
+	}
+}
+
+func TestKrakenGetPricesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"error": []string{"EService:Unavailable"}})
+	}))
+	defer server.Close()
+
+	_, err := NewKraken(server.URL).GetPrices(context.Background(), []string{"bitcoin"}, "usd")
+	if err == nil || !strings.Contains(err.Error(), "EService:Unavailable") {
+		t.Fatalf("errore atteso, ottenuto %v", err)
+	}
+}
+
+func TestKrakenGetPricesInCurrency(t *testing.T) {
+	server, _ := newKrakenServer(t, map[string]krakenPair{
+		"XBTEUR": {"XXBTZEUR", "60000"},
+		"ETHXBT": {"XETHXXBT", "0.05"},
+	})
+	kraken := NewKraken(server.URL)
+
+	prices, err := kraken.GetPrices(context.Background(), []string{"bitcoin"}, "eur")
+	if err != nil || len(prices) != 1 || prices["bitcoin"] != 60000 {
+		t.Fatalf("prezzi = %v, err = %v", prices, err)
+	}
+
+	prices, err = kraken.GetPrices(context.Background(), []string{"ethereum", "bitcoin"}, "btc")
+	if err != nil || len(prices) != 1 || prices["ethereum"] != 0.05 {
+		t.Fatalf("prezzi = %v, err = %v", prices, err)
+	}
+}
+
+func TestKrakenCoinID(t *testing.T) {
+	assets := map[string]string{"XBT": "bitcoin", "ETH": "ethereum", "USDT": "tether", "SOL": "solana"}
+
+	tests := []struct {
+		pairName, quote, want string
+	}{
+		{"XXBTZUSD", "USD", "bitcoin"},
+		{"XBTUSD", "USD", "bitcoin"},
+		{"USDTZUSD", "USD", "tether"},
+		{"USDTUSD", "USD", "tether"},
+		{"XETHXXBT", "XBT", "ethereum"},
+		{"SOLEUR", "EUR", "solana"},
+		{"XXBTZEUR", "USD", ""},
+	}
+	for _, tt := range tests {
+		got, _ := krakenCoinID(tt.pairName, tt.quote, assets)
+		if got != tt.want {
+			t.Errorf("krakenCoinID(%q, %q) = %q, atteso %q", tt.pairName, tt.quote, got, tt.want)
+		}
+	}
+}
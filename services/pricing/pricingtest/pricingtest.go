@@ -0,0 +1,161 @@
+// Package pricingtest fornisce una sorgente di prezzi in memoria da usare nei test
+// al posto di CoinGecko e degli exchange.
+package pricingtest
+
+import (
+	"context"
+	"crypto-tracker/services/pricing"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Provider è un pricing.PriceProvider (e pricing.QuoteProvider) che restituisce i prezzi
+// impostati con Set e tiene il conto delle richieste ricevute
+type Provider struct {
+	name string
+
+	mu      sync.Mutex
+	quotes  map[string]pricing.Quote // indicizzate per "valuta:id"
+	err     error
+	calls   int
+	release chan struct{} // se non nil, le richieste attendono la sua chiusura
+}
+
+// New crea una sorgente finta senza prezzi
+func New(name string) *Provider {
+	return &Provider{name: name, quotes: make(map[string]pricing.Quote)}
+}
+
+// Name restituisce il nome della sorgente
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Set imposta il prezzo di una criptovaluta in una valuta
+func (p *Provider) Set(coinID, currency string, price float64) *Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	quote := p.quotes[key(coinID, currency)]
+	quote.CoinID, quote.Currency, quote.Price = coinID, currency, price
+	p.quotes[key(coinID, currency)] = quote
+	return p
+}
+
+// SetChange24h imposta la variazione nelle 24 ore di una criptovaluta già presente
+func (p *Provider) SetChange24h(coinID, currency string, change float64) *Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	quote := p.quotes[key(coinID, currency)]
+	quote.Change24h = &change
+	p.quotes[key(coinID, currency)] = quote
+	return p
+}
+
+// Remove elimina il prezzo di una criptovaluta, che da quel momento risulta non disponibile
+func (p *Provider) Remove(coinID, currency string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.quotes, key(coinID, currency))
+}
+
+// Fail fa fallire tutte le richieste successive con err; con nil la sorgente torna a rispondere
+func (p *Provider) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+// Hold sospende le richieste successive finché non viene chiamata la funzione restituita
+func (p *Provider) Hold() (release func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch := make(chan struct{})
+	p.release = ch
+	return sync.OnceFunc(func() { close(ch) })
+}
+
+// Calls restituisce il numero di richieste ricevute
+func (p *Provider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+// GetPrice restituisce il prezzo di una singola criptovaluta
+func (p *Provider) GetPrice(ctx context.Context, coinID, currency string) (float64, error) {
+	quote, err := p.GetQuote(ctx, coinID, currency)
+	return quote.Price, err
+}
+
+// GetPrices restituisce i prezzi impostati per le criptovalute richieste
+func (p *Provider) GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error) {
+	quotes, err := p.GetQuotes(ctx, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(quotes))
+	for id, quote := range quotes {
+		prices[id] = quote.Price
+	}
+	return prices, nil
+}
+
+// GetQuote restituisce la quotazione di una singola criptovaluta
+func (p *Provider) GetQuote(ctx context.Context, coinID, currency string) (pricing.Quote, error) {
+	quotes, err := p.GetQuotes(ctx, []string{coinID}, currency)
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+
+	quote, ok := quotes[coinID]
+	if !ok {
+		return pricing.Quote{}, fmt.Errorf("%w per %s in %s (%s)", pricing.ErrPriceNotFound, coinID, currency, p.name)
+	}
+	return quote, nil
+}
+
+// GetQuotes restituisce le quotazioni impostate per le criptovalute richieste, aggiornate all'istante corrente
+func (p *Provider) GetQuotes(ctx context.Context, coinIDs []string, currency string) (map[string]pricing.Quote, error) {
+	p.mu.Lock()
+	p.calls++
+	release := p.release
+	p.mu.Unlock()
+
+	if release != nil {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	asOf := time.Now()
+	quotes := make(map[string]pricing.Quote, len(coinIDs))
+	for _, id := range coinIDs {
+		if quote, ok := p.quotes[key(id, currency)]; ok {
+			quote.AsOf = asOf
+			quotes[id] = quote
+		}
+	}
+	return quotes, nil
+}
+
+// key restituisce la chiave di un prezzo per una criptovaluta in una valuta
+func key(coinID, currency string) string {
+	return currency + ":" + coinID
+}
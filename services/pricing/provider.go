@@ -0,0 +1,45 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPriceNotFound indica che la sorgente non ha restituito un prezzo per la criptovaluta richiesta
+var ErrPriceNotFound = errors.New("prezzo non trovato")
+
+// PriceProvider rappresenta una sorgente di prezzi per le criptovalute.
+// Tutte le componenti (handler REST, AlertMonitor, bot Telegram) dipendono da questa
+// interfaccia, così da poter sostituire la sorgente o usarne una finta nei test.
+type PriceProvider interface {
+	// GetPrice restituisce il prezzo di una criptovaluta (es. "bitcoin") nella valuta indicata (es. "usd")
+	GetPrice(ctx context.Context, coinID, currency string) (float64, error)
+
+	// GetPrices restituisce i prezzi di più criptovalute nella valuta indicata, indicizzati per ID.
+	// Gli ID per cui la sorgente non ha un prezzo (o non supporta la valuta) sono assenti dalla mappa.
+	GetPrices(ctx context.Context, coinIDs []string, currency string) (map[string]float64, error)
+}
+
+// sourceName restituisce il nome di una sorgente, se disponibile, per i log
+func sourceName(p PriceProvider) string {
+	if named, ok := p.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", p)
+}
+
+// getSinglePrice implementa GetPrice tramite GetPrices per le sorgenti che lavorano a lotti
+func getSinglePrice(ctx context.Context, p PriceProvider, coinID, currency string) (float64, error) {
+	prices, err := p.GetPrices(ctx, []string{coinID}, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	price, ok := prices[coinID]
+	if !ok {
+		return 0, fmt.Errorf("%w per %s in %s (%s)", ErrPriceNotFound, coinID, currency, sourceName(p))
+	}
+
+	return price, nil
+}
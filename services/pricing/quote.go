@@ -0,0 +1,89 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Quote è un prezzo accompagnato dalla valuta, dall'istante in cui è stato ottenuto
+// dalla sorgente e, se disponibile, dalla variazione percentuale nelle ultime 24 ore
+type Quote struct {
+	CoinID    string    `json:"id"`
+	Currency  string    `json:"currency"`
+	Price     float64   `json:"price"`
+	Change24h *float64  `json:"change_24h,omitempty"`
+	AsOf      time.Time `json:"as_of"`
+}
+
+// Age restituisce da quanto tempo il prezzo è stato ottenuto
+func (q Quote) Age() time.Duration {
+	return time.Since(q.AsOf)
+}
+
+// QuoteProvider è implementato dalle sorgenti in grado di fornire, oltre al prezzo,
+// il momento a cui si riferisce ed eventualmente la variazione nelle ultime 24 ore
+type QuoteProvider interface {
+	GetQuote(ctx context.Context, coinID, currency string) (Quote, error)
+	GetQuotes(ctx context.Context, coinIDs []string, currency string) (map[string]Quote, error)
+}
+
+// GetQuote ottiene un prezzo con il relativo timestamp da qualsiasi PriceProvider.
+// Se la sorgente non implementa QuoteProvider il prezzo è considerato aggiornato all'istante corrente.
+func GetQuote(ctx context.Context, prices PriceProvider, coinID, currency string) (Quote, error) {
+	if qp, ok := prices.(QuoteProvider); ok {
+		return qp.GetQuote(ctx, coinID, currency)
+	}
+
+	price, err := prices.GetPrice(ctx, coinID, currency)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	return Quote{CoinID: coinID, Currency: currency, Price: price, AsOf: time.Now()}, nil
+}
+
+// GetQuotes ottiene i prezzi di più criptovalute con il relativo timestamp da qualsiasi PriceProvider.
+// Per le sorgenti che non implementano QuoteProvider la variazione nelle 24 ore non è disponibile.
+func GetQuotes(ctx context.Context, prices PriceProvider, coinIDs []string, currency string) (map[string]Quote, error) {
+	if qp, ok := prices.(QuoteProvider); ok {
+		return qp.GetQuotes(ctx, coinIDs, currency)
+	}
+
+	result, err := prices.GetPrices(ctx, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	asOf := time.Now()
+	quotes := make(map[string]Quote, len(result))
+	for id, price := range result {
+		quotes[id] = Quote{CoinID: id, Currency: currency, Price: price, AsOf: asOf}
+	}
+
+	return quotes, nil
+}
+
+// getSingleQuote implementa GetQuote tramite GetQuotes per le sorgenti che lavorano a lotti
+func getSingleQuote(ctx context.Context, qp QuoteProvider, coinID, currency string) (Quote, error) {
+	quotes, err := qp.GetQuotes(ctx, []string{coinID}, currency)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	quote, ok := quotes[coinID]
+	if !ok {
+		return Quote{}, fmt.Errorf("%w per %s in %s", ErrPriceNotFound, coinID, currency)
+	}
+
+	return quote, nil
+}
+
+// pricesFromQuotes estrae i soli prezzi da un insieme di quotazioni
+func pricesFromQuotes(quotes map[string]Quote) map[string]float64 {
+	prices := make(map[string]float64, len(quotes))
+	for id, quote := range quotes {
+		prices[id] = quote.Price
+	}
+	return prices
+}
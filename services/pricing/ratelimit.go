@@ -0,0 +1,155 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen indica che la sorgente è temporaneamente esclusa dopo troppi errori consecutivi
+var ErrCircuitOpen = errors.New("circuit breaker aperto: sorgente temporaneamente non disponibile")
+
+// rateLimiter distribuisce le richieste in modo uniforme per rispettare un budget al minuto
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter crea un limitatore per requestsPerMinute richieste al minuto (nessun limite se <= 0)
+func newRateLimiter(requestsPerMinute int) *rateLimiter {
+	var interval time.Duration
+	if requestsPerMinute > 0 {
+		interval = time.Minute / time.Duration(requestsPerMinute)
+	}
+	return &rateLimiter{interval: interval}
+}
+
+// Wait attende il prossimo slot disponibile o la cancellazione del contesto
+func (rl *rateLimiter) Wait(ctx context.Context) error {
+	if rl.interval == 0 {
+		return nil
+	}
+
+	rl.mu.Lock()
+	now := time.Now()
+	slot := rl.next
+	if slot.Before(now) {
+		slot = now
+	}
+	rl.next = slot.Add(rl.interval)
+	rl.mu.Unlock()
+
+	return sleepContext(ctx, slot.Sub(now))
+}
+
+// circuitBreaker esclude la sorgente per un periodo dopo un numero di fallimenti consecutivi
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool // una richiesta di prova è in corso dopo il periodo di esclusione
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow indica se una richiesta può essere eseguita. Scaduto il periodo di esclusione passa
+// una sola richiesta di prova: il suo esito chiude il circuito o lo riapre, e fino ad allora
+// le altre richieste vengono rifiutate.
+func (cb *circuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.failures < cb.threshold {
+		return nil
+	}
+	if cb.probing || time.Now().Before(cb.openUntil) {
+		return ErrCircuitOpen
+	}
+
+	cb.probing = true
+	return nil
+}
+
+// Success azzera il conteggio dei fallimenti e chiude il circuito
+func (cb *circuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+	cb.probing = false
+}
+
+// Release conclude una richiesta il cui esito non dice nulla sulla disponibilità della sorgente
+// (es. una richiesta rifiutata con un errore 4xx): il circuito resta nello stato in cui si trova
+// e, se era la richiesta di prova, la prossima richiesta potrà riprovare
+func (cb *circuitBreaker) Release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
+
+// Failure registra un fallimento e apre il circuito al raggiungimento della soglia.
+// Restituisce true se il circuito risulta aperto.
+func (cb *circuitBreaker) Failure() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.probing = false
+	if cb.failures >= cb.threshold {
+		cb.openUntil = time.Now().Add(cb.cooldown)
+		return true
+	}
+	return false
+}
+
+// backoff calcola l'attesa prima del tentativo n (a partire da 0) con backoff
+// esponenziale e jitter completo, limitata a max
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base << attempt
+	if d <= 0 || d > max {
+		d = max
+	}
+	return rand.N(d) + time.Millisecond
+}
+
+// retryAfter interpreta l'header Retry-After (secondi o data HTTP)
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext attende d o la cancellazione del contesto
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,54 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerSingleHalfOpenProbe(t *testing.T) {
+	cb := newCircuitBreaker(1, 10*time.Millisecond)
+	if !cb.Failure() {
+		t.Fatal("circuito aperto atteso dopo il primo fallimento")
+	}
+	if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("errore = %v, atteso ErrCircuitOpen durante l'esclusione", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Scaduta l'esclusione passa una sola richiesta di prova
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("richiesta di prova rifiutata: %v", err)
+	}
+	if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("errore = %v, attesa una sola richiesta di prova", err)
+	}
+
+	// Un esito neutro libera la prova senza chiudere il circuito
+	cb.Release()
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("nuova richiesta di prova rifiutata: %v", err)
+	}
+
+	cb.Success()
+	for range 2 {
+		if err := cb.Allow(); err != nil {
+			t.Fatalf("circuito chiuso atteso dopo il successo: %v", err)
+		}
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	cb := newCircuitBreaker(1, 10*time.Millisecond)
+	cb.Failure()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("richiesta di prova rifiutata: %v", err)
+	}
+	cb.Failure()
+	if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("errore = %v, atteso ErrCircuitOpen dopo una prova fallita", err)
+	}
+}
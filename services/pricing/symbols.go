@@ -0,0 +1,68 @@
+package pricing
+
+import "sync"
+
+// tickerSymbols associa gli ID CoinGecko ai simboli usati dagli exchange.
+// Le sorgenti basate su coppie di trading (Binance, Kraken) supportano solo le criptovalute elencate qui.
+var tickerSymbols = map[string]string{
+	"bitcoin":       "BTC",
+	"ethereum":      "ETH",
+	"tether":        "USDT",
+	"binancecoin":   "BNB",
+	"solana":        "SOL",
+	"ripple":        "XRP",
+	"usd-coin":      "USDC",
+	"cardano":       "ADA",
+	"dogecoin":      "DOGE",
+	"tron":          "TRX",
+	"avalanche-2":   "AVAX",
+	"polkadot":      "DOT",
+	"chainlink":     "LINK",
+	"litecoin":      "LTC",
+	"bitcoin-cash":  "BCH",
+	"stellar":       "XLM",
+	"uniswap":       "UNI",
+	"cosmos":        "ATOM",
+	"near":          "NEAR",
+	"matic-network": "MATIC",
+	"shiba-inu":     "SHIB",
+	"monero":        "XMR",
+	"algorand":      "ALGO",
+	"aptos":         "APT",
+	"arbitrum":      "ARB",
+	"optimism":      "OP",
+	"pepe":          "PEPE",
+	"sui":           "SUI",
+}
+
+// tickerSymbol restituisce il simbolo di una criptovaluta dato il suo ID CoinGecko
+func tickerSymbol(coinID string) (string, bool) {
+	symbol, ok := tickerSymbols[coinID]
+	return symbol, ok
+}
+
+// pairSet contiene le coppie di trading che un exchange non riconosce (es. criptovalute rimosse
+// dalle quotazioni), escluse dalle richieste successive fino al riavvio
+type pairSet struct {
+	mu    sync.RWMutex
+	pairs map[string]bool
+}
+
+// add aggiunge una coppia all'insieme
+func (s *pairSet) add(pair string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pairs == nil {
+		s.pairs = make(map[string]bool)
+	}
+	s.pairs[pair] = true
+}
+
+// has indica se la coppia è nell'insieme
+func (s *pairSet) has(pair string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pairs[pair]
+}
@@ -0,0 +1,511 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// alertOptions contiene le opzioni comuni ai comandi di creazione degli alert
+type alertOptions struct {
+	TriggerMode       string
+	Rolling           bool // riferimento sulle 24 ore (solo alert percentuali)
+	Recurring         bool
+	CooldownMinutes   int
+	HysteresisPercent float64
+	MaxFires          int
+	ActiveIn          time.Duration // ritardo dell'attivazione rispetto alla creazione
+	ExpiresIn         time.Duration // durata dell'alert dall'attivazione
+}
+
+// alertOptionsUsage descrive le opzioni accettate da parseAlertOptions
+const alertOptionsUsage = "[--level] [--repeat] [--cooldown 1h] [--band 2] [--max 5] [--after 2h] [--for 7d]"
+
+// parseAlertOptions separa le opzioni (es. --level, --cooldown 1h) dagli argomenti posizionali
+func parseAlertOptions(args []string) (alertOptions, []string, error) {
+	options := alertOptions{TriggerMode: models.TriggerModeCrossing}
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		// Le opzioni senza valore
+		switch arg {
+		case "--level":
+			options.TriggerMode = models.TriggerModeLevel
+			continue
+		case "--24h":
+			options.Rolling = true
+			continue
+		case "--repeat":
+			options.Recurring = true
+			continue
+		}
+
+		// Le opzioni seguite da un valore
+		if i+1 >= len(args) {
+			return alertOptions{}, nil, i18n.Errorf("args.option_without_value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		switch arg {
+		case "--cooldown":
+			d, err := parseDuration(value)
+			if err != nil {
+				return alertOptions{}, nil, err
+			}
+			// Il cooldown è espresso in minuti: una durata inferiore diventerebbe zero
+			if d < time.Minute {
+				return alertOptions{}, nil, i18n.Errorf("args.invalid_cooldown", value)
+			}
+			options.Recurring = true
+			options.CooldownMinutes = int(d / time.Minute)
+		case "--for", "--after":
+			d, err := parseDuration(value)
+			if err != nil {
+				return alertOptions{}, nil, err
+			}
+			if arg == "--for" {
+				options.ExpiresIn = d
+			} else {
+				options.ActiveIn = d
+			}
+		case "--band":
+			band, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+			if err != nil || band < 0 {
+				return alertOptions{}, nil, i18n.Errorf("args.invalid_band")
+			}
+			options.Recurring = true
+			options.HysteresisPercent = band
+		case "--max":
+			maxFires, err := strconv.Atoi(value)
+			if err != nil || maxFires < 0 {
+				return alertOptions{}, nil, i18n.Errorf("args.invalid_max")
+			}
+			options.Recurring = true
+			options.MaxFires = maxFires
+		default:
+			return alertOptions{}, nil, i18n.Errorf("args.unknown_option", arg)
+		}
+	}
+
+	return options, positional, nil
+}
+
+// apply copia le opzioni sull'alert; attivazione e scadenza sono calcolate a partire da CreatedAt
+func (o alertOptions) apply(alert *models.Alert) {
+	alert.TriggerMode = o.TriggerMode
+	alert.Recurring = o.Recurring
+	alert.CooldownMinutes = o.CooldownMinutes
+	alert.HysteresisPercent = o.HysteresisPercent
+	alert.MaxFires = o.MaxFires
+	if o.Rolling {
+		alert.Baseline = models.BaselineRolling
+	}
+
+	start := alert.CreatedAt
+	if o.ActiveIn > 0 {
+		start = start.Add(o.ActiveIn)
+		alert.ActiveFrom = &start
+	}
+	if o.ExpiresIn > 0 {
+		expiresAt := start.Add(o.ExpiresIn)
+		alert.ExpiresAt = &expiresAt
+	}
+}
+
+// parseDuration interpreta una durata come "30m", "6h", "7d" o "2w"
+func parseDuration(value string) (time.Duration, error) {
+	invalid := i18n.Errorf("args.invalid_duration", value)
+
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	if unit > 0 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n <= 0 {
+			return 0, invalid
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, invalid
+	}
+	return d, nil
+}
+
+// createAlertArgs contiene gli argomenti del comando /create_alert
+type createAlertArgs struct {
+	CoinID    string
+	Direction string
+	Threshold float64
+	Currency  string
+	Options   alertOptions
+}
+
+// parseCreateAlertArgs interpreta la sintassi
+// /create_alert <crypto_id> [> | < | <> | above | below | cross] <threshold_price> [valuta] [opzioni]
+func parseCreateAlertArgs(args []string) (createAlertArgs, error) {
+	parsed := createAlertArgs{
+		Direction: models.DirectionAbove,
+		Currency:  pricing.DefaultCurrency,
+	}
+
+	options, args, err := parseAlertOptions(args)
+	if err != nil {
+		return createAlertArgs{}, err
+	}
+	if options.Rolling {
+		return createAlertArgs{}, i18n.Errorf("args.rolling_percent_only")
+	}
+	parsed.Options = options
+
+	if len(args) < 2 {
+		return createAlertArgs{}, i18n.Errorf("args.create_alert_usage", alertOptionsUsage)
+	}
+
+	// Converti l'ID in lowercase
+	parsed.CoinID = strings.ToLower(args[0])
+
+	direction, rest, err := splitDirection(args[1:])
+	if err != nil {
+		return createAlertArgs{}, err
+	}
+	if direction != "" {
+		parsed.Direction = direction
+	}
+
+	if len(rest) < 1 {
+		return createAlertArgs{}, i18n.Errorf("args.threshold_required")
+	}
+
+	if parsed.Threshold, err = strconv.ParseFloat(rest[0], 64); err != nil {
+		return createAlertArgs{}, i18n.Errorf("common.invalid_price")
+	}
+	if parsed.Threshold <= 0 {
+		return createAlertArgs{}, i18n.Errorf("args.invalid_threshold")
+	}
+
+	// La valuta è opzionale (es. /create_alert bitcoin 60000 eur)
+	if len(rest) > 1 {
+		if parsed.Currency, err = pricing.ParseCurrency(rest[1]); err != nil {
+			return createAlertArgs{}, err
+		}
+	}
+
+	return parsed, nil
+}
+
+// percentAlertArgs contiene gli argomenti del comando /percent_alert
+type percentAlertArgs struct {
+	CoinID        string
+	Direction     string
+	PercentChange float64
+	Currency      string
+	Options       alertOptions
+}
+
+// parsePercentAlertArgs interpreta la sintassi
+// /percent_alert <crypto_id> <[+|-]percentuale> [valuta] [--24h] [opzioni]
+// Un segno + indica solo rialzo, un segno - solo ribasso, nessun segno (o ±) entrambe le direzioni.
+func parsePercentAlertArgs(args []string) (percentAlertArgs, error) {
+	parsed := percentAlertArgs{
+		Direction: models.DirectionCross,
+		Currency:  pricing.DefaultCurrency,
+	}
+
+	options, positional, err := parseAlertOptions(args)
+	if err != nil {
+		return percentAlertArgs{}, err
+	}
+	parsed.Options = options
+
+	if len(positional) < 2 {
+		return percentAlertArgs{}, i18n.Errorf("args.percent_alert_usage", alertOptionsUsage)
+	}
+
+	parsed.CoinID = strings.ToLower(positional[0])
+
+	value := strings.TrimSuffix(positional[1], "%")
+	switch {
+	case strings.HasPrefix(value, "+"):
+		parsed.Direction = models.DirectionAbove
+	case strings.HasPrefix(value, "-"):
+		parsed.Direction = models.DirectionBelow
+	}
+	value = strings.TrimLeft(value, "+-±")
+
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent <= 0 {
+		return percentAlertArgs{}, i18n.Errorf("args.invalid_percent")
+	}
+	parsed.PercentChange = percent
+
+	if len(positional) > 2 {
+		if parsed.Currency, err = pricing.ParseCurrency(positional[2]); err != nil {
+			return percentAlertArgs{}, err
+		}
+	}
+
+	return parsed, nil
+}
+
+// portfolioAlertArgs contiene gli argomenti del comando /portfolio_alert
+type portfolioAlertArgs struct {
+	Direction string
+	Threshold float64
+	Currency  string
+	Options   alertOptions
+}
+
+// parsePortfolioAlertArgs interpreta la sintassi
+// /portfolio_alert [>|<|<>] <valore> [valuta] [opzioni]
+func parsePortfolioAlertArgs(args []string) (portfolioAlertArgs, error) {
+	parsed := portfolioAlertArgs{
+		Direction: models.DirectionAbove,
+		Currency:  pricing.DefaultCurrency,
+	}
+
+	options, positional, err := parseAlertOptions(args)
+	if err != nil {
+		return portfolioAlertArgs{}, err
+	}
+	if options.Rolling {
+		return portfolioAlertArgs{}, i18n.Errorf("args.rolling_percent_only")
+	}
+	parsed.Options = options
+
+	direction, rest, err := splitDirection(positional)
+	if err != nil {
+		return portfolioAlertArgs{}, err
+	}
+	if direction != "" {
+		parsed.Direction = direction
+	}
+
+	if len(rest) < 1 {
+		return portfolioAlertArgs{}, i18n.Errorf("args.portfolio_alert_usage", alertOptionsUsage)
+	}
+
+	if parsed.Threshold, err = strconv.ParseFloat(rest[0], 64); err != nil || parsed.Threshold <= 0 {
+		return portfolioAlertArgs{}, i18n.Errorf("args.invalid_value")
+	}
+
+	if len(rest) > 1 {
+		if parsed.Currency, err = pricing.ParseCurrency(rest[1]); err != nil {
+			return portfolioAlertArgs{}, err
+		}
+	}
+
+	return parsed, nil
+}
+
+// pnlAlertArgs contiene gli argomenti del comando /pnl_alert
+type pnlAlertArgs struct {
+	CoinID    string
+	Direction string
+	Threshold float64 // P&L percentuale con segno
+	Options   alertOptions
+}
+
+// parsePnLAlertArgs interpreta la sintassi /pnl_alert <crypto_id> <[+|-]percentuale> [opzioni].
+// Una percentuale positiva scatta al rialzo (take profit), una negativa al ribasso (stop loss).
+func parsePnLAlertArgs(args []string) (pnlAlertArgs, error) {
+	options, positional, err := parseAlertOptions(args)
+	if err != nil {
+		return pnlAlertArgs{}, err
+	}
+	if options.Rolling {
+		return pnlAlertArgs{}, i18n.Errorf("args.rolling_percent_only")
+	}
+
+	if len(positional) < 2 {
+		return pnlAlertArgs{}, i18n.Errorf("args.pnl_alert_usage", alertOptionsUsage)
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSuffix(positional[1], "%"), 64)
+	if err != nil || threshold == 0 {
+		return pnlAlertArgs{}, i18n.Errorf("args.invalid_pnl_percent")
+	}
+
+	parsed := pnlAlertArgs{
+		CoinID:    strings.ToLower(positional[0]),
+		Direction: models.DirectionAbove,
+		Threshold: threshold,
+		Options:   options,
+	}
+	if threshold < 0 {
+		parsed.Direction = models.DirectionBelow
+	}
+
+	return parsed, nil
+}
+
+// splitDirection estrae una direzione opzionale all'inizio degli argomenti.
+// Se il primo argomento è un numero la direzione non è indicata e viene restituita vuota.
+func splitDirection(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", args, nil
+	}
+
+	if _, err := strconv.ParseFloat(args[0], 64); err == nil {
+		return "", args, nil
+	}
+
+	direction, err := models.ParseDirection(args[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return direction, args[1:], nil
+}
+
+// directionSymbols contiene il simbolo mostrato davanti alla soglia per ciascuna direzione
+var directionSymbols = map[string]string{
+	models.DirectionAbove: "≥",
+	models.DirectionBelow: "≤",
+	models.DirectionCross: "↕",
+}
+
+// formatThreshold formatta la soglia di un alert con la sua direzione
+// (es. "≤ $60000.00", "± 8.00% da $150.00" per gli alert percentuali oppure "≥ +30.00%" per quelli sul P&L)
+func formatThreshold(lang string, alert *models.Alert) string {
+	symbol, ok := directionSymbols[alert.Direction]
+	if !ok {
+		symbol = directionSymbols[models.DirectionAbove]
+	}
+
+	switch alert.Type {
+	case models.AlertTypePositionPnL:
+		return fmt.Sprintf("%s %+.2f%%", symbol, alert.ThresholdPrice)
+	case models.AlertTypePercent:
+		sign := "±"
+		switch alert.Direction {
+		case models.DirectionAbove:
+			sign = "+"
+		case models.DirectionBelow:
+			sign = "-"
+		}
+
+		reference := i18n.T(lang, "format.reference_from", pricing.FormatAmount(alert.ReferencePrice, alert.Currency))
+		if alert.Baseline == models.BaselineRolling {
+			reference = i18n.T(lang, "format.reference_24h")
+		}
+		return fmt.Sprintf("%s%.2f%% %s", sign, alert.PercentChange, reference)
+	}
+
+	return symbol + " " + pricing.FormatAmount(alert.ThresholdPrice, alert.Currency)
+}
+
+// alertSubject restituisce ciò che l'alert osserva: la criptovaluta, il portafoglio o la posizione
+func alertSubject(lang string, alert *models.Alert) string {
+	switch alert.Type {
+	case models.AlertTypePortfolioValue:
+		return i18n.T(lang, "format.subject_portfolio", strings.ToUpper(alert.Currency))
+	case models.AlertTypePositionPnL:
+		return i18n.T(lang, "format.subject_position", alert.CryptoID)
+	default:
+		return alert.CryptoID
+	}
+}
+
+// formatSubject formatta ciò che l'alert osserva con la sua etichetta (es. "Crypto: bitcoin" oppure "Portafoglio: USD")
+func formatSubject(lang string, alert *models.Alert) string {
+	switch alert.Type {
+	case models.AlertTypePortfolioValue:
+		return i18n.T(lang, "format.label_portfolio", strings.ToUpper(alert.Currency))
+	case models.AlertTypePositionPnL:
+		return i18n.T(lang, "format.label_position", alert.CryptoID)
+	default:
+		return i18n.T(lang, "format.label_crypto", alert.CryptoID)
+	}
+}
+
+// formatCurrent formatta l'ultimo valore osservato da un alert con la sua etichetta
+// (es. "Prezzo attuale: $61000.00" oppure "P&L posizione: +12.50%")
+func formatCurrent(lang string, alert *models.Alert) string {
+	switch alert.Type {
+	case models.AlertTypePortfolioValue:
+		return i18n.T(lang, "format.current_portfolio", formatObserved(alert, alert.CurrentPrice))
+	case models.AlertTypePositionPnL:
+		return i18n.T(lang, "format.current_position", formatObserved(alert, alert.CurrentPrice))
+	default:
+		return i18n.T(lang, "format.current_price", formatObserved(alert, alert.CurrentPrice))
+	}
+}
+
+// formatObserved formatta un valore osservato da un alert: un importo oppure, per gli alert sul P&L, una percentuale
+func formatObserved(alert *models.Alert, value float64) string {
+	if alert.Type == models.AlertTypePositionPnL {
+		return fmt.Sprintf("%+.2f%%", value)
+	}
+	return pricing.FormatAmount(value, alert.Currency)
+}
+
+// formatRepeat descrive la ricorrenza di un alert (es. "🔁 Ricorrente: 2/5 scatti, cooldown 60 min")
+func formatRepeat(lang string, alert *models.Alert) string {
+	if !alert.Recurring {
+		return ""
+	}
+
+	fires := strconv.Itoa(alert.FireCount)
+	if alert.MaxFires > 0 {
+		fires += "/" + strconv.Itoa(alert.MaxFires)
+	}
+
+	details := []string{i18n.T(lang, "format.repeat_fires", fires)}
+	if alert.CooldownMinutes > 0 {
+		details = append(details, i18n.T(lang, "format.repeat_cooldown", alert.CooldownMinutes))
+	}
+	if alert.HysteresisPercent > 0 {
+		details = append(details, i18n.T(lang, "format.repeat_band", alert.HysteresisPercent))
+	}
+
+	return i18n.T(lang, "format.repeat", strings.Join(details, ", "))
+}
+
+// formatWindow descrive la finestra di validità di un alert (es. "⏱ Scade il: 24/10/2026 18:30 CEST")
+func formatWindow(lang string, loc *time.Location, alert *models.Alert) string {
+	var parts []string
+	if alert.ActiveFrom != nil {
+		parts = append(parts, i18n.T(lang, "format.active_from", alert.ActiveFrom.In(loc).Format(dateTimeLayout)))
+	}
+	if alert.ExpiresAt != nil {
+		parts = append(parts, i18n.T(lang, "format.expires_at", alert.ExpiresAt.In(loc).Format(dateTimeLayout)))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "⏱ " + strings.Join(parts, " | ")
+}
+
+// alertStatus restituisce lo stato di un alert da mostrare all'utente
+func alertStatus(lang string, alert *models.Alert, now time.Time) string {
+	switch {
+	case alert.Expired:
+		return i18n.T(lang, "status.expired")
+	case alert.Triggered:
+		return i18n.T(lang, "status.triggered")
+	case !alert.IsActiveAt(now):
+		return i18n.T(lang, "status.scheduled")
+	default:
+		return i18n.T(lang, "status.waiting")
+	}
+}
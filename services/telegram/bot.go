@@ -1,8 +1,13 @@
 package telegram
 
 import (
-	"crypto-tracker/controllers"
+	"context"
+	"crypto-tracker/i18n"
 	"crypto-tracker/models"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/history"
+	"crypto-tracker/services/portfolio"
+	"crypto-tracker/services/pricing"
 	"fmt"
 	"log"
 	"strconv"
@@ -14,19 +19,26 @@ import (
 	"gorm.io/gorm"
 )
 
-// Timezone italiano (UTC+2)
-var italianTimezone, _ = time.LoadLocation("Europe/Rome")
+// priceRequestTimeout limita la durata delle richieste di prezzo fatte dai comandi
+const priceRequestTimeout = 15 * time.Second
 
 // TelegramBot gestisce l'interazione con il bot Telegram
 type TelegramBot struct {
-	bot      *tgbotapi.BotAPI
-	db       *gorm.DB
-	chatIDs  map[int64]bool // Mappa delle chat IDs attive
-	chatLock sync.RWMutex   // Per accesso thread-safe alla mappa
+	bot     *tgbotapi.BotAPI
+	db      *gorm.DB
+	prices  pricing.PriceProvider
+	history *history.Store
+	coins   *coins.Registry
+
+	conversations    map[int64]*conversation // Conversazioni guidate in corso, per chat
+	conversationLock sync.Mutex
+
+	settings     map[int64]cachedChat // Preferenze (lingua e fuso orario) di ciascuna chat già incontrata
+	settingsLock sync.RWMutex
 }
 
 // NewTelegramBot crea una nuova istanza del bot Telegram
-func NewTelegramBot(token string, db *gorm.DB) (*TelegramBot, error) {
+func NewTelegramBot(token string, db *gorm.DB, prices pricing.PriceProvider, store *history.Store, registry *coins.Registry) (*TelegramBot, error) {
 	bot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, fmt.Errorf("errore nell'inizializzazione del bot: %w", err)
@@ -37,7 +49,12 @@ func NewTelegramBot(token string, db *gorm.DB) (*TelegramBot, error) {
 	return &TelegramBot{
 		bot:     bot,
 		db:      db,
-		chatIDs: make(map[int64]bool),
+		prices:  prices,
+		history: store,
+		coins:   registry,
+
+		conversations: make(map[int64]*conversation),
+		settings:      make(map[int64]cachedChat),
 	}, nil
 }
 
@@ -62,14 +79,24 @@ func (t *TelegramBot) Start() {
 			messageCount++
 			log.Printf("[Telegram] Ricevuto update #%d da Telegram", messageCount)
 
+			// Pressione di un pulsante inline
+			if update.CallbackQuery != nil {
+				log.Printf("[Telegram] Callback da %s: %s", update.CallbackQuery.From.UserName, update.CallbackQuery.Data)
+				go t.handleCallback(update.CallbackQuery)
+				continue
+			}
+
+			// Il bot è stato bloccato, sbloccato o rimosso da un gruppo
+			if update.MyChatMember != nil {
+				go t.handleMyChatMember(update.MyChatMember)
+				continue
+			}
+
 			if update.Message == nil {
 				log.Println("[Telegram] Update senza messaggio, ignoro")
 				continue
 			}
 
-			// Memorizza l'ID della chat per le notifiche future
-			t.registerChatID(update.Message.Chat.ID)
-
 			chatID := update.Message.Chat.ID
 			log.Printf("[Telegram] Processando messaggio da chat ID %d: %s", chatID, update.Message.Text)
 
@@ -79,49 +106,57 @@ func (t *TelegramBot) Start() {
 		log.Println("[Telegram] Loop di aggiornamenti interrotto! Il bot non riceverà più messaggi!")
 	}()
 
-	log.Println("[Telegram] Bot avviato correttamente e in ascolto di messaggi")
-}
-
-// registerChatID registra un ID chat per future notifiche
-func (t *TelegramBot) registerChatID(chatID int64) {
-	t.chatLock.Lock()
-	defer t.chatLock.Unlock()
-
-	if _, exists := t.chatIDs[chatID]; !exists {
-		t.chatIDs[chatID] = true
-		log.Printf("[Telegram] Nuovo utente registrato con chat ID: %d", chatID)
-	}
-}
+	// Annulla periodicamente le conversazioni guidate inattive
+	go t.sweepConversations()
 
-// GetAllChatIDs restituisce tutti gli ID chat registrati
-func (t *TelegramBot) GetAllChatIDs() []int64 {
-	t.chatLock.RLock()
-	defer t.chatLock.RUnlock()
-
-	chatIDs := make([]int64, 0, len(t.chatIDs))
-	for id := range t.chatIDs {
-		chatIDs = append(chatIDs, id)
-	}
-
-	return chatIDs
+	log.Println("[Telegram] Bot avviato correttamente e in ascolto di messaggi")
 }
 
 // handleMessage gestisce i messaggi in arrivo
 func (t *TelegramBot) handleMessage(message *tgbotapi.Message) {
 	log.Printf("[Telegram] Messaggio da %s: %s", message.From.UserName, message.Text)
 
+	// Registra la chat (con la sua lingua al primo messaggio) e l'ultimo accesso
+	t.registerChat(message.Chat, message.From, true)
+	lang := t.language(message.Chat.ID)
+
+	// Un testo prosegue l'eventuale conversazione guidata in corso, un comando la interrompe
 	if !message.IsCommand() {
-		t.sendMessage(message.Chat.ID, "Invia un comando, ad esempio /help o /price bitcoin")
+		if t.continueConversation(message) {
+			return
+		}
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "bot.send_command"))
 		return
 	}
+	_, inConversation := t.takeConversation(message.Chat.ID)
 
 	switch message.Command() {
+	case "cancel":
+		if inConversation {
+			t.sendMessageRemovingKeyboard(message.Chat.ID, i18n.T(lang, "bot.cancelled"))
+			return
+		}
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "bot.nothing_to_cancel"))
+	case "new_alert":
+		t.handleNewAlert(message)
 	case "start", "help":
 		t.handleHelp(message)
 	case "price":
 		t.handlePrice(message)
+	case "search":
+		t.handleSearch(message)
+	case "range":
+		t.handleRange(message)
+	case "chart":
+		t.handleChart(message)
 	case "create_alert":
 		t.handleCreateAlert(message)
+	case "percent_alert":
+		t.handlePercentAlert(message)
+	case "portfolio_alert":
+		t.handlePortfolioAlert(message)
+	case "pnl_alert":
+		t.handlePnLAlert(message)
 	case "update_alert":
 		t.handleUpdateAlert(message)
 	case "alerts":
@@ -132,309 +167,609 @@ func (t *TelegramBot) handleMessage(message *tgbotapi.Message) {
 		t.handleGetAlert(message)
 	case "delete_alert":
 		t.handleDeleteAlert(message)
+	case "history":
+		t.handleHistory(message)
+	case "buy":
+		t.handleBuy(message)
+	case "sell":
+		t.handleSell(message)
+	case "portfolio":
+		t.handlePortfolio(message)
+	case "watch":
+		t.handleWatch(message)
+	case "unwatch":
+		t.handleUnwatch(message)
+	case "watchlist":
+		t.handleWatchlist(message)
+	case "digest":
+		t.handleDigest(message)
+	case "language":
+		t.handleLanguage(message)
+	case "timezone":
+		t.handleTimezone(message)
 	default:
-		t.sendMessage(message.Chat.ID, "Comando non riconosciuto. Usa /help per vedere i comandi disponibili.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "bot.unknown_command"))
 	}
 }
 
 // handleHelp gestisce il comando /help
 func (t *TelegramBot) handleHelp(message *tgbotapi.Message) {
-	helpText := `
-Comandi disponibili:
-/price <crypto_id> - Ottiene il prezzo attuale (es: /price bitcoin)
-/create_alert <crypto_id> <threshold_price> - Crea un nuovo alert (es: /create_alert bitcoin 30000)
-/update_alert <id> <threshold_price> - Aggiorna un alert esistente (es: /update_alert 1 32000)
-/alerts - Mostra tutti gli alert
-/active_alerts - Mostra solo gli alert attivi (non triggerati)
-/alert <id> - Mostra i dettagli di un alert specifico
-/delete_alert <id> - Elimina un alert specifico
-/help - Mostra questo messaggio
-`
-	t.sendMessage(message.Chat.ID, helpText)
+	t.sendMessage(message.Chat.ID, i18n.T(t.language(message.Chat.ID), "help.text"))
 }
 
 // handlePrice gestisce il comando /price
 func (t *TelegramBot) handlePrice(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
 	args := strings.Fields(message.CommandArguments())
 	if len(args) < 1 {
-		t.sendMessage(message.Chat.ID, "Specifica l'ID della criptovaluta. Esempio: /price bitcoin")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "price.usage"))
 		return
 	}
 
-	// Converti l'ID in lowercase per evitare problemi con maiuscole/minuscole
-	coinID := strings.ToLower(args[0])
-	price, err := controllers.GetPriceUSD(coinID)
+	// Risolvi simboli e nomi (es. "btc" o "Bitcoin") nell'ID CoinGecko
+	coinID := t.coins.Resolve(args[0])
+
+	// La valuta è opzionale (es. /price bitcoin eur)
+	currency := pricing.DefaultCurrency
+	if len(args) > 1 {
+		var err error
+		if currency, err = pricing.ParseCurrency(args[1]); err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+			return
+		}
+	}
+
+	quote, err := t.getQuote(coinID, currency)
 	if err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore: %v", err))
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err)+t.didYouMean(lang, coinID))
 		return
 	}
 
-	t.sendMessage(message.Chat.ID, fmt.Sprintf("💰 %s: $%.2f USD", coinID, price))
+	// FormatAmount indica già la valuta, con il simbolo (es. "$") o con il codice (es. "CHF")
+	t.sendMessage(message.Chat.ID, fmt.Sprintf("💰 %s: %s\n%s", coinID, pricing.FormatAmount(quote.Price, currency), formatQuoteFreshness(lang, loc, quote)))
 }
 
 // handleCreateAlert gestisce il comando /create_alert
 func (t *TelegramBot) handleCreateAlert(message *tgbotapi.Message) {
-	args := strings.Fields(message.CommandArguments())
-	if len(args) < 2 {
-		t.sendMessage(message.Chat.ID, "Formato: /create_alert <crypto_id> <threshold_price>")
-		return
-	}
-
-	// Converti l'ID in lowercase
-	coinID := strings.ToLower(args[0])
-	thresholdPrice, err := strconv.ParseFloat(args[1], 64)
+	lang := t.language(message.Chat.ID)
+	args, err := parseCreateAlertArgs(strings.Fields(message.CommandArguments()))
 	if err != nil {
-		t.sendMessage(message.Chat.ID, "Prezzo non valido. Usa un numero decimale.")
+		t.sendMessage(message.Chat.ID, i18n.Message(lang, err))
 		return
 	}
+	args.CoinID = t.coins.Resolve(args.CoinID)
+
+	t.sendMessage(message.Chat.ID, t.createPriceAlert(message.Chat.ID, args))
+}
+
+// createPriceAlert crea un alert di prezzo per la chat e restituisce il messaggio di risposta
+// (di conferma o di errore); è usata da /create_alert e dalla creazione guidata /new_alert
+func (t *TelegramBot) createPriceAlert(chatID int64, args createAlertArgs) string {
+	lang := t.language(chatID)
+	loc := t.location(chatID)
 
 	// Usa la stessa logica di validazione presente in controllers.CreateAlert
-	price, err := controllers.GetPriceUSD(coinID)
+	price, err := t.getPrice(args.CoinID, args.Currency)
 	if err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore: Impossibile ottenere il prezzo per '%s'. Verifica che l'ID sia corretto.", coinID))
-		return
+		return i18n.T(lang, "common.price_unavailable", args.CoinID) + t.didYouMean(lang, args.CoinID)
 	}
 
 	// Crea l'alert utilizzando le stesse logiche dei controller
 	// Usa UTC per i timestamp nel database
 	now := time.Now().UTC()
 	alert := models.Alert{
-		CryptoID:       coinID,
-		ThresholdPrice: thresholdPrice,
+		CryptoID:       args.CoinID,
+		Currency:       args.Currency,
+		Direction:      args.Direction,
+		ThresholdPrice: args.Threshold,
 		CurrentPrice:   price,
+		ObservedAt:     &now,
 		Triggered:      false,
 		CreatedAt:      now,
 		UpdatedAt:      now,
-		UserChatID:     message.Chat.ID, // Salva l'ID della chat dell'utente
+		UserChatID:     chatID, // Salva l'ID della chat dell'utente
+	}
+	args.Options.apply(&alert)
+
+	if err := t.db.Create(&alert).Error; err != nil {
+		return i18n.T(lang, "alert.create_error", err)
+	}
+
+	// Converti la data nel fuso orario della chat solo per la visualizzazione
+	createdAtLocal := alert.CreatedAt.In(loc)
+	response := i18n.T(lang, "alert.created",
+		alert.ID, alert.CryptoID, formatThreshold(lang, &alert), pricing.FormatAmount(alert.CurrentPrice, alert.Currency), createdAtLocal.Format(dateTimeLayout))
+
+	if repeat := formatRepeat(lang, &alert); repeat != "" {
+		response += "\n" + repeat
+	}
+	if window := formatWindow(lang, loc, &alert); window != "" {
+		response += "\n" + window
+	}
+
+	// Avvisa se il prezzo soddisfa già la condizione
+	if warning := alert.CreationWarning(lang, price); warning != "" {
+		response += "\n\n⚠️ " + warning
+	}
+
+	return response
+}
+
+// handlePercentAlert gestisce il comando /percent_alert
+func (t *TelegramBot) handlePercentAlert(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	args, err := parsePercentAlertArgs(strings.Fields(message.CommandArguments()))
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.Message(lang, err))
+		return
+	}
+	args.CoinID = t.coins.Resolve(args.CoinID)
+
+	// Il prezzo attuale verifica l'ID e fornisce il riferimento per la variazione
+	quote, err := t.getQuote(args.CoinID, args.Currency)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.price_unavailable", args.CoinID))
+		return
+	}
+
+	now := time.Now().UTC()
+	alert := models.Alert{
+		CryptoID:      args.CoinID,
+		Type:          models.AlertTypePercent,
+		Currency:      args.Currency,
+		Direction:     args.Direction,
+		PercentChange: args.PercentChange,
+		Baseline:      models.BaselineFixed,
+		CurrentPrice:  quote.Price,
+		ObservedAt:    &now,
+		Triggered:     false,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		UserChatID:    message.Chat.ID,
+	}
+	args.Options.apply(&alert)
+
+	if err := alert.SetReference(quote.Price, quote.Change24h); err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+		return
 	}
 
 	if err := t.db.Create(&alert).Error; err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore nella creazione dell'alert: %v", err))
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.create_error", err))
 		return
 	}
 
-	// Converti la data in fuso orario italiano solo per la visualizzazione
-	createdAtLocal := alert.CreatedAt.In(italianTimezone)
-	t.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Alert creato! ID: %d\nCrypto: %s\nSoglia: $%.2f\nPrezzo attuale: $%.2f\nCreato il: %s (CET)",
-		alert.ID, alert.CryptoID, alert.ThresholdPrice, alert.CurrentPrice, createdAtLocal.Format("02/01/2006 15:04")))
+	response := i18n.T(lang, "alert.percent_created",
+		alert.ID, alert.CryptoID, formatThreshold(lang, &alert), pricing.FormatAmount(alert.CurrentPrice, alert.Currency),
+		alert.ChangePercent(alert.CurrentPrice), alert.CreatedAt.In(loc).Format(dateTimeLayout))
+
+	if repeat := formatRepeat(lang, &alert); repeat != "" {
+		response += "\n" + repeat
+	}
+	if window := formatWindow(lang, loc, &alert); window != "" {
+		response += "\n" + window
+	}
+
+	if warning := alert.CreationWarning(lang, quote.Price); warning != "" {
+		response += "\n\n⚠️ " + warning
+	}
+
+	t.sendMessage(message.Chat.ID, response)
 }
 
 // handleUpdateAlert gestisce il comando /update_alert
 func (t *TelegramBot) handleUpdateAlert(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
 	args := strings.Fields(message.CommandArguments())
 	if len(args) < 2 {
-		t.sendMessage(message.Chat.ID, "Formato: /update_alert <id> <threshold_price>")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.update_usage"))
 		return
 	}
 
-	// Estrai ID e nuovo prezzo
+	// Estrai l'ID; direzione opzionale e nuovo prezzo sono interpretati da updateAlertThreshold
 	id, err := strconv.Atoi(args[0])
 	if err != nil || id <= 0 {
-		t.sendMessage(message.Chat.ID, "ID non valido. Usa un numero intero positivo.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.invalid_id"))
 		return
 	}
 
-	thresholdPrice, err := strconv.ParseFloat(args[1], 64)
+	t.updateAlertThreshold(message.Chat.ID, id, args[1:])
+}
+
+// updateAlertThreshold aggiorna la soglia di un alert dell'utente a partire dagli argomenti
+// [>|<|<>] <threshold_price> [reset], usati sia da /update_alert sia dal pulsante di modifica.
+// Restituisce false se gli argomenti non sono validi, così che la modifica possa essere ripetuta.
+func (t *TelegramBot) updateAlertThreshold(chatID int64, id int, args []string) bool {
+	lang := t.language(chatID)
+	direction, args, err := splitDirection(args)
 	if err != nil {
-		t.sendMessage(message.Chat.ID, "Prezzo non valido. Usa un numero decimale.")
-		return
+		t.sendMessage(chatID, i18n.Message(lang, err))
+		return false
+	}
+	if len(args) < 1 {
+		t.sendMessage(chatID, i18n.T(lang, "alert.update_usage"))
+		return false
+	}
+
+	thresholdPrice, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		t.sendMessage(chatID, i18n.T(lang, "common.invalid_price"))
+		return false
 	}
 
 	// Verifica che l'alert esista e appartenga all'utente corrente
 	var alert models.Alert
-	if err := t.db.Where("id = ? AND user_chat_id = ?", id, message.Chat.ID).First(&alert).Error; err != nil {
-		t.sendMessage(message.Chat.ID, "Alert non trovato o non hai i permessi per modificarlo.")
-		return
+	if err := t.db.Where("id = ? AND user_chat_id = ?", id, chatID).First(&alert).Error; err != nil {
+		t.sendMessage(chatID, i18n.T(lang, "alert.not_found_edit"))
+		return true
+	}
+	before := alert
+
+	// Solo gli alert sul P&L accettano un valore con segno; per gli altri la soglia deve essere positiva
+	switch {
+	case alert.Type == models.AlertTypePositionPnL && thresholdPrice == 0:
+		t.sendMessage(chatID, i18n.T(lang, "args.invalid_pnl_percent"))
+		return false
+	case alert.Type == models.AlertTypePercent && thresholdPrice <= 0:
+		t.sendMessage(chatID, i18n.T(lang, "args.invalid_percent"))
+		return false
+	case alert.Type != models.AlertTypePositionPnL && thresholdPrice <= 0:
+		t.sendMessage(chatID, i18n.T(lang, "args.invalid_threshold"))
+		return false
+	}
+
+	// Aggiorna i campi dell'alert (la direzione solo se indicata esplicitamente).
+	// Per gli alert percentuali il valore indicato è la nuova variazione, per quelli
+	// sul P&L il segno indica la direzione se non specificata.
+	switch alert.Type {
+	case models.AlertTypePercent:
+		alert.PercentChange = thresholdPrice
+	case models.AlertTypePositionPnL:
+		alert.ThresholdPrice = thresholdPrice
+		if direction == "" {
+			direction = models.DirectionAbove
+			if thresholdPrice < 0 {
+				direction = models.DirectionBelow
+			}
+		}
+	default:
+		alert.ThresholdPrice = thresholdPrice
+	}
+	if direction != "" {
+		alert.Direction = direction
 	}
-
-	// Aggiorna i campi dell'alert
-	alert.ThresholdPrice = thresholdPrice
 
 	// Aggiorna anche il prezzo corrente e reimposta lo stato triggered se necessario
 	wasTriggered := alert.Triggered
 	resetTrigger := false
 
-	// Se viene specificato un terzo argomento "reset", reimposta lo stato triggered
-	if len(args) > 2 && args[2] == "reset" {
+	// Se dopo la soglia viene specificato "reset", reimposta lo stato triggered
+	if len(args) > 1 && args[1] == "reset" {
 		resetTrigger = true
 	}
 
-	// Aggiorna il prezzo corrente
-	price, err := controllers.GetPriceUSD(alert.CryptoID)
+	// Aggiorna il prezzo corrente (o il valore del portafoglio per gli alert sul portafoglio)
+	current, err := t.currentValue(&alert)
 	if err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Avviso: Impossibile ottenere il prezzo aggiornato: %v", err))
+		t.sendMessage(chatID, i18n.T(lang, "alert.update_price_warning", err))
 	} else {
-		alert.CurrentPrice = price
+		alert.Observe(current, time.Now().UTC())
 	}
 
-	// Se il prezzo attuale è sotto la soglia o è stato richiesto un reset, reimposta lo stato
-	if resetTrigger || alert.CurrentPrice < alert.ThresholdPrice {
+	// Se è stato richiesto un reset, o se il prezzo attuale non soddisfa più la condizione di un alert
+	// che non ha esaurito gli scatti, reimposta lo stato
+	if resetTrigger {
+		alert.Rearm()
+	} else if !alert.ConditionMet(alert.CurrentPrice) && !alert.Exhausted() {
 		alert.Triggered = false
 	}
 
 	alert.UpdatedAt = time.Now().UTC()
 
-	// Salva le modifiche
-	if err := t.db.Save(&alert).Error; err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore nell'aggiornamento dell'alert: %v", err))
-		return
+	// Salva le modifiche registrandole nella cronologia
+	var events []models.AlertEvent
+	if event, changed := alert.NewUpdateEvent(&before, alert.UpdatedAt); changed {
+		events = append(events, event)
+	}
+	if wasTriggered && !alert.Triggered {
+		events = append(events, alert.NewEvent(models.AlertEventReset, models.ResetManual, alert.UpdatedAt))
+	}
+	if err := alert.SaveWithEvents(t.db, events...); err != nil {
+		t.sendMessage(chatID, i18n.T(lang, "alert.update_error", err))
+		return true
 	}
 
 	// Prepara il messaggio di risposta
 	statusChange := ""
 	if wasTriggered && !alert.Triggered {
-		statusChange = "\n⚠️ Lo stato è stato reimpostato da triggerato a attivo!"
+		statusChange = "\n" + i18n.T(lang, "alert.status_reset")
 	}
 
-	status := "⏳ In attesa"
-	if alert.Triggered {
-		status = "✅ Triggerato"
-	}
+	status := alertStatus(lang, &alert, time.Now())
 
-	t.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Alert aggiornato! ID: %d\nCrypto: %s\nNuova soglia: $%.2f\nPrezzo attuale: $%.2f\nStato: %s%s",
-		alert.ID, alert.CryptoID, alert.ThresholdPrice, alert.CurrentPrice, status, statusChange))
+	t.sendMessage(chatID, i18n.T(lang, "alert.updated",
+		alert.ID, formatSubject(lang, &alert), formatThreshold(lang, &alert), formatCurrent(lang, &alert), status, statusChange))
+	return true
 }
 
 // handleGetAlerts gestisce il comando /alerts
 func (t *TelegramBot) handleGetAlerts(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
 	var alerts []models.Alert
 
 	// Filtra gli alert per l'ID della chat dell'utente corrente
 	if err := t.db.Where("user_chat_id = ?", message.Chat.ID).Find(&alerts).Error; err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore nel recupero degli alert: %v", err))
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.list_error", err))
 		return
 	}
 
 	if len(alerts) == 0 {
-		t.sendMessage(message.Chat.ID, "Non hai alert salvati.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.list_empty"))
 		return
 	}
 
 	var response strings.Builder
-	response.WriteString("📊 I tuoi alert:\n\n")
+	response.WriteString(i18n.T(lang, "alert.list_header"))
 
+	now := time.Now()
 	for _, alert := range alerts {
-		status := "⏳ In attesa"
+		status := alertStatus(lang, &alert, now)
 		triggerInfo := ""
 
-		if alert.Triggered {
-			status = "✅ Triggerato"
-			if alert.NotifiedAt != nil {
-				triggerInfo = fmt.Sprintf("Triggerato il: %s (CET)\n", alert.NotifiedAt.In(italianTimezone).Format("02/01/2006 15:04"))
-			}
+		if alert.Triggered && alert.NotifiedAt != nil {
+			triggerInfo = i18n.T(lang, "alert.triggered_at", alert.NotifiedAt.In(loc).Format(dateTimeLayout)) + "\n"
 		}
 
-		createdAt := fmt.Sprintf("Creato il: %s (CET)\n", alert.CreatedAt.In(italianTimezone).Format("02/01/2006 15:04"))
+		createdAt := i18n.T(lang, "alert.created_at", alert.CreatedAt.In(loc).Format(dateTimeLayout)) + "\n"
+		if repeat := formatRepeat(lang, &alert); repeat != "" {
+			createdAt += repeat + "\n"
+		}
+		if window := formatWindow(lang, loc, &alert); window != "" {
+			createdAt += window + "\n"
+		}
 
-		response.WriteString(fmt.Sprintf("ID: %d | %s\nCrypto: %s\nSoglia: $%.2f\nPrezzo attuale: $%.2f\n%s%s\n",
-			alert.ID, status, alert.CryptoID, alert.ThresholdPrice, alert.CurrentPrice, createdAt, triggerInfo))
+		response.WriteString(i18n.T(lang, "alert.list_item",
+			alert.ID, status, formatSubject(lang, &alert), formatThreshold(lang, &alert), formatCurrent(lang, &alert), createdAt, triggerInfo))
 	}
 
-	t.sendMessage(message.Chat.ID, response.String())
+	t.sendMessageWithKeyboard(message.Chat.ID, response.String(), alertKeyboard(lang, alerts...))
 }
 
 // handleGetActiveAlerts gestisce il comando /active_alerts
 func (t *TelegramBot) handleGetActiveAlerts(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
 	var alerts []models.Alert
 
-	// Filtra gli alert per l'ID della chat dell'utente corrente e non triggerati
-	if err := t.db.Where("user_chat_id = ? AND triggered = ?", message.Chat.ID, false).Find(&alerts).Error; err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore nel recupero degli alert attivi: %v", err))
+	// Filtra gli alert per l'ID della chat dell'utente corrente, non triggerati e non scaduti
+	if err := t.db.Where("user_chat_id = ? AND triggered = ? AND expired = ?", message.Chat.ID, false, false).Find(&alerts).Error; err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.active_list_error", err))
 		return
 	}
 
 	if len(alerts) == 0 {
-		t.sendMessage(message.Chat.ID, "Non hai alert attivi.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.active_list_empty"))
 		return
 	}
 
 	var response strings.Builder
-	response.WriteString("⚡ I tuoi alert attivi:\n\n")
+	response.WriteString(i18n.T(lang, "alert.active_list_header"))
 
 	for _, alert := range alerts {
-		createdAt := fmt.Sprintf("Creato il: %s (CET)", alert.CreatedAt.In(italianTimezone).Format("02/01/2006 15:04"))
+		createdAt := i18n.T(lang, "alert.created_at", alert.CreatedAt.In(loc).Format(dateTimeLayout))
+		if window := formatWindow(lang, loc, &alert); window != "" {
+			createdAt += "\n" + window
+		}
 
-		response.WriteString(fmt.Sprintf("ID: %d\nCrypto: %s\nSoglia: $%.2f\nPrezzo attuale: $%.2f\n%s\n\n",
-			alert.ID, alert.CryptoID, alert.ThresholdPrice, alert.CurrentPrice, createdAt))
+		response.WriteString(i18n.T(lang, "alert.active_list_item",
+			alert.ID, formatSubject(lang, &alert), formatThreshold(lang, &alert), formatCurrent(lang, &alert), createdAt))
 	}
 
-	t.sendMessage(message.Chat.ID, response.String())
+	t.sendMessageWithKeyboard(message.Chat.ID, response.String(), alertKeyboard(lang, alerts...))
 }
 
 // handleGetAlert gestisce il comando /alert
 func (t *TelegramBot) handleGetAlert(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
 	args := strings.Fields(message.CommandArguments())
 	if len(args) < 1 {
-		t.sendMessage(message.Chat.ID, "Specifica l'ID dell'alert. Esempio: /alert 1")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.id_required", "/alert"))
 		return
 	}
 
 	id, err := strconv.Atoi(args[0])
 	if err != nil || id <= 0 {
-		t.sendMessage(message.Chat.ID, "ID non valido. Usa un numero intero positivo.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.invalid_id"))
 		return
 	}
 
 	var alert models.Alert
 	// Filtra per id dell'alert e utente corrente
 	if err := t.db.Where("id = ? AND user_chat_id = ?", id, message.Chat.ID).First(&alert).Error; err != nil {
-		t.sendMessage(message.Chat.ID, "Alert non trovato o non hai i permessi per visualizzarlo.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.not_found_view"))
 		return
 	}
 
-	status := "⏳ In attesa"
-	if alert.Triggered {
-		status = "✅ Triggerato"
-	}
+	status := alertStatus(lang, &alert, time.Now())
 
-	createdAt := fmt.Sprintf("Creato il: %s (CET)", alert.CreatedAt.In(italianTimezone).Format("02/01/2006 15:04"))
+	createdAt := i18n.T(lang, "alert.created_at", alert.CreatedAt.In(loc).Format(dateTimeLayout))
 
-	response := fmt.Sprintf("🔔 Alert #%d\n\nCrypto: %s\nSoglia: $%.2f\nPrezzo attuale: $%.2f\nStato: %s\n%s",
-		alert.ID, alert.CryptoID, alert.ThresholdPrice, alert.CurrentPrice, status, createdAt)
+	response := i18n.T(lang, "alert.detail",
+		alert.ID, formatSubject(lang, &alert), formatThreshold(lang, &alert), formatCurrent(lang, &alert), status, createdAt)
 
 	if alert.Triggered && alert.NotifiedAt != nil {
-		response += fmt.Sprintf("\nTriggerato il: %s (CET)", alert.NotifiedAt.In(italianTimezone).Format("02/01/2006 15:04"))
+		response += "\n" + i18n.T(lang, "alert.triggered_at", alert.NotifiedAt.In(loc).Format(dateTimeLayout))
 	}
 
-	t.sendMessage(message.Chat.ID, response)
+	if repeat := formatRepeat(lang, &alert); repeat != "" {
+		response += "\n" + repeat
+	}
+	if window := formatWindow(lang, loc, &alert); window != "" {
+		response += "\n" + window
+	}
+
+	t.sendMessageWithKeyboard(message.Chat.ID, response, alertKeyboard(lang, alert))
+}
+
+// historyLimit è il numero massimo di eventi mostrati dal comando /history
+const historyLimit = 20
+
+// eventLabels contiene la chiave del catalogo con la descrizione di ciascun tipo di evento
+var eventLabels = map[string]string{
+	models.AlertEventTrigger: "event.trigger",
+	models.AlertEventReset:   "event.reset",
+	models.AlertEventUpdate:  "event.update",
+	models.AlertEventExpiry:  "event.expiry",
+}
+
+// handleHistory gestisce il comando /history
+func (t *TelegramBot) handleHistory(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	args := strings.Fields(message.CommandArguments())
+	if len(args) < 1 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.id_required", "/history"))
+		return
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.invalid_id"))
+		return
+	}
+
+	var alert models.Alert
+	if err := t.db.Where("id = ? AND user_chat_id = ?", id, message.Chat.ID).First(&alert).Error; err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.not_found_view"))
+		return
+	}
+
+	var events []models.AlertEvent
+	if err := t.db.Where("alert_id = ?", alert.ID).Order("created_at DESC, id DESC").Limit(historyLimit).Find(&events).Error; err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "history.error", err))
+		return
+	}
+
+	if len(events) == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "history.empty", alert.ID))
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(i18n.T(lang, "history.header", alert.ID, alertSubject(lang, &alert), formatThreshold(lang, &alert)))
+
+	for _, event := range events {
+		label := event.Type
+		if key, ok := eventLabels[event.Type]; ok {
+			label = i18n.T(lang, key)
+		}
+
+		response.WriteString(i18n.T(lang, "history.item", label,
+			event.CreatedAt.In(loc).Format(dateTimeLayout), formatObserved(&alert, event.Price)))
+		if details := event.Describe(lang); details != "" {
+			response.WriteString("   " + details + "\n")
+		}
+	}
+
+	t.sendMessage(message.Chat.ID, response.String())
 }
 
 // handleDeleteAlert gestisce il comando /delete_alert
 func (t *TelegramBot) handleDeleteAlert(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
 	args := strings.Fields(message.CommandArguments())
 	if len(args) < 1 {
-		t.sendMessage(message.Chat.ID, "Specifica l'ID dell'alert. Esempio: /delete_alert 1")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.id_required", "/delete_alert"))
 		return
 	}
 
 	id, err := strconv.Atoi(args[0])
 	if err != nil || id <= 0 {
-		t.sendMessage(message.Chat.ID, "ID non valido. Usa un numero intero positivo.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.invalid_id"))
 		return
 	}
 
 	// Prima verifica che l'alert esista e appartenga all'utente corrente
 	var alert models.Alert
 	if err := t.db.Where("id = ? AND user_chat_id = ?", id, message.Chat.ID).First(&alert).Error; err != nil {
-		t.sendMessage(message.Chat.ID, "Alert non trovato o non hai i permessi per eliminarlo.")
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.not_found_delete"))
 		return
 	}
 
-	// Procedi con l'eliminazione
-	if err := t.db.Delete(&alert).Error; err != nil {
-		t.sendMessage(message.Chat.ID, fmt.Sprintf("Errore nella cancellazione: %v", err))
+	// Procedi con l'eliminazione, insieme alla cronologia
+	if err := alert.DeleteWithEvents(t.db); err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.delete_error", err))
 		return
 	}
 
-	t.sendMessage(message.Chat.ID, fmt.Sprintf("🗑️ Alert #%d eliminato con successo.", id))
+	t.sendMessage(message.Chat.ID, i18n.T(lang, "alert.deleted", id))
+}
+
+// currentValue restituisce il valore attuale osservato da un alert: il prezzo della criptovaluta
+// oppure il valore del portafoglio o il P&L della posizione
+func (t *TelegramBot) currentValue(alert *models.Alert) (float64, error) {
+	if !alert.IsPortfolio() {
+		return t.getPrice(alert.CryptoID, alert.Currency)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	return portfolio.Current(ctx, t.db, t.prices, alert)
+}
+
+// getPrice ottiene il prezzo corrente di una criptovaluta dalla sorgente configurata
+func (t *TelegramBot) getPrice(coinID, currency string) (float64, error) {
+	quote, err := t.getQuote(coinID, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	return quote.Price, nil
+}
+
+// getQuote ottiene il prezzo corrente di una criptovaluta insieme all'istante a cui si riferisce
+func (t *TelegramBot) getQuote(coinID, currency string) (pricing.Quote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	return pricing.GetQuote(ctx, t.prices, coinID, currency)
+}
+
+// formatQuoteFreshness descrive quanto è aggiornato un prezzo (es. "🕒 Aggiornato alle 15:04:05, 30s fa")
+func formatQuoteFreshness(lang string, loc *time.Location, quote pricing.Quote) string {
+	age := quote.Age().Round(time.Second)
+	return i18n.T(lang, "price.freshness", quote.AsOf.In(loc).Format(secondsLayout), age)
 }
 
 // sendMessage invia un messaggio a una chat
 func (t *TelegramBot) sendMessage(chatID int64, text string) {
+	t.deliver(chatID, text)
+}
+
+// deliver invia un messaggio a una chat e restituisce l'eventuale errore di invio,
+// per i messaggi il cui recapito va verificato (es. i digest pianificati)
+func (t *TelegramBot) deliver(chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
-	_, err := t.bot.Send(msg)
-	if err != nil {
-		log.Printf("[Telegram] Errore nell'invio del messaggio: %v", err)
+	if _, err := t.bot.Send(msg); err != nil {
+		t.sendFailed(chatID, err)
+		return err
+	}
+	return nil
+}
+
+// sendMessageWithKeyboard invia un messaggio a una chat con una tastiera inline (se presente)
+func (t *TelegramBot) sendMessageWithKeyboard(chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	if _, err := t.bot.Send(msg); err != nil {
+		t.sendFailed(chatID, err)
 	}
 }
 
@@ -445,6 +780,10 @@ func (t *TelegramBot) GetNotificationChannel() chan *models.Alert {
 	// Goroutine che ascolta le notifiche e le invia
 	go func() {
 		for alert := range notifyCh {
+			if alert.Expired {
+				t.sendExpiryNotification(alert)
+				continue
+			}
 			t.sendAlertNotification(alert)
 		}
 	}()
@@ -454,15 +793,38 @@ func (t *TelegramBot) GetNotificationChannel() chan *models.Alert {
 
 // sendAlertNotification invia una notifica quando un alert viene triggerato
 func (t *TelegramBot) sendAlertNotification(alert *models.Alert) {
-	// Invia la notifica solo all'utente che ha creato l'alert
+	// Invia la notifica solo all'utente che ha creato l'alert, nella sua lingua
 	chatID := alert.UserChatID
+	lang := t.language(chatID)
+	loc := t.location(chatID)
+
+	// Formatta l'ora corrente nel fuso orario della chat per visualizzazione
+	currentTime := time.Now().In(loc)
+
+	message := i18n.T(lang, "notification.triggered",
+		alert.ID, formatSubject(lang, alert), formatThreshold(lang, alert), formatCurrent(lang, alert), currentTime.Format(dateTimeLayout))
 
-	// Formatta l'ora corrente in timezone italiano per visualizzazione
-	currentTime := time.Now().In(italianTimezone)
+	// Per gli alert percentuali mostra la variazione effettivamente raggiunta
+	if alert.Type == models.AlertTypePercent {
+		message += "\n" + i18n.T(lang, "notification.change",
+			alert.ChangePercent(alert.CurrentPrice), pricing.FormatAmount(alert.ReferencePrice, alert.Currency))
+	}
 
-	message := fmt.Sprintf("🚨 ALERT TRIGGERATO! 🚨\n\nID: %d\nCrypto: %s\nSoglia: $%.2f\nPrezzo attuale: $%.2f\nData: %s (CET)",
-		alert.ID, alert.CryptoID, alert.ThresholdPrice, alert.CurrentPrice, currentTime.Format("02/01/2006 15:04"))
+	// Per gli alert ricorrenti indica quante volte è scattato
+	if repeat := formatRepeat(lang, alert); repeat != "" {
+		message += "\n" + repeat
+	}
 
 	log.Printf("[Telegram] Invio notifica di alert triggerato all'utente %d", chatID)
-	t.sendMessage(chatID, message)
+	t.sendMessageWithKeyboard(chatID, message, alertKeyboard(lang, *alert))
+}
+
+// sendExpiryNotification avvisa il proprietario che un alert è scaduto senza mai scattare
+func (t *TelegramBot) sendExpiryNotification(alert *models.Alert) {
+	lang := t.language(alert.UserChatID)
+	message := i18n.T(lang, "notification.expired",
+		alert.ID, formatSubject(lang, alert), formatThreshold(lang, alert), formatCurrent(lang, alert))
+
+	log.Printf("[Telegram] Invio notifica di alert scaduto all'utente %d", alert.UserChatID)
+	t.sendMessage(alert.UserChatID, message)
 }
@@ -0,0 +1,194 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Azioni dei pulsanti associati agli alert; il campo data dei pulsanti ha la forma "alert:<azione>:<id>"
+const (
+	callbackPrefix = "alert"
+	callbackDelete = "delete"
+	callbackReset  = "reset"
+	callbackSnooze = "snooze"
+	callbackEdit   = "edit"
+)
+
+// snoozeDuration è la durata della sospensione applicata dal pulsante Snooze
+const snoozeDuration = time.Hour
+
+// maxKeyboardAlerts limita gli alert con pulsanti in un singolo messaggio (Telegram accetta al massimo 100 pulsanti)
+const maxKeyboardAlerts = 20
+
+// alertKeyboard crea la tastiera inline con le azioni per gli alert indicati: con un solo alert
+// i pulsanti hanno un'etichetta descrittiva, con più alert una riga per alert con il relativo ID
+func alertKeyboard(lang string, alerts ...models.Alert) *tgbotapi.InlineKeyboardMarkup {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	labels := [...]string{i18n.T(lang, "button.delete"), i18n.T(lang, "button.reset"), i18n.T(lang, "button.snooze"), i18n.T(lang, "button.edit")}
+	actions := [...]string{callbackDelete, callbackReset, callbackSnooze, callbackEdit}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, alert := range alerts {
+		if i == maxKeyboardAlerts {
+			break
+		}
+
+		row := make([]tgbotapi.InlineKeyboardButton, 0, len(actions))
+		for j, action := range actions {
+			label := labels[j]
+			if len(alerts) > 1 {
+				label = fmt.Sprintf("%s #%d", strings.Fields(label)[0], alert.ID)
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, callbackData(action, alert.ID)))
+		}
+		rows = append(rows, row)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// callbackData costruisce il campo data di un pulsante per un alert
+func callbackData(action string, id uint) string {
+	return fmt.Sprintf("%s:%s:%d", callbackPrefix, action, id)
+}
+
+// parseCallbackData interpreta il campo data di un pulsante per un alert
+func parseCallbackData(data string) (action string, id int, ok bool) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 || parts[0] != callbackPrefix {
+		return "", 0, false
+	}
+
+	id, err := strconv.Atoi(parts[2])
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+
+	return parts[1], id, true
+}
+
+// handleCallback gestisce la pressione di un pulsante inline
+func (t *TelegramBot) handleCallback(query *tgbotapi.CallbackQuery) {
+	action, id, ok := parseCallbackData(query.Data)
+	if !ok || query.Message == nil {
+		t.answerCallback(query, i18n.T(i18n.Match(query.From.LanguageCode), "callback.unknown_action"), true)
+		return
+	}
+	chatID := query.Message.Chat.ID
+	t.registerChat(query.Message.Chat, query.From, false)
+	lang := t.language(chatID)
+	loc := t.location(chatID)
+
+	// Verifica che l'alert esista e appartenga all'utente corrente, come per /delete_alert
+	var alert models.Alert
+	if err := t.db.Where("id = ? AND user_chat_id = ?", id, chatID).First(&alert).Error; err != nil {
+		t.answerCallback(query, i18n.T(lang, "alert.not_found_edit"), true)
+		return
+	}
+
+	switch action {
+	case callbackDelete:
+		if err := alert.DeleteWithEvents(t.db); err != nil {
+			t.answerCallback(query, i18n.T(lang, "alert.delete_error", err), true)
+			return
+		}
+		t.answerCallback(query, i18n.T(lang, "callback.deleted", alert.ID), false)
+		t.removeAlertButtons(query.Message, alert.ID)
+
+	case callbackReset:
+		if !alert.Triggered {
+			t.answerCallback(query, i18n.T(lang, "callback.already_active", alert.ID), false)
+			return
+		}
+
+		alert.Rearm()
+		alert.UpdatedAt = time.Now().UTC()
+		if err := alert.SaveWithEvents(t.db, alert.NewEvent(models.AlertEventReset, models.ResetManual, alert.UpdatedAt)); err != nil {
+			t.answerCallback(query, i18n.T(lang, "alert.update_error", err), true)
+			return
+		}
+		t.answerCallback(query, i18n.T(lang, "callback.reset", alert.ID), false)
+
+	case callbackSnooze:
+		before := alert
+		now := time.Now().UTC()
+		until := now.Add(snoozeDuration)
+		if alert.ExpiresAt != nil && !alert.ExpiresAt.After(until) {
+			t.answerCallback(query, i18n.T(lang, "callback.expires_before_snooze", alert.ID), true)
+			return
+		}
+
+		// La sospensione sposta l'inizio della finestra di attivazione, a meno che non inizi già più tardi
+		layout := timeLayout
+		if alert.ActiveFrom != nil && alert.ActiveFrom.After(until) {
+			until = *alert.ActiveFrom
+			layout = dateTimeLayout
+		}
+		alert.ActiveFrom = &until
+		alert.UpdatedAt = now
+		var events []models.AlertEvent
+		if event, changed := alert.NewUpdateEvent(&before, now); changed {
+			events = append(events, event)
+		}
+		if err := alert.SaveWithEvents(t.db, events...); err != nil {
+			t.answerCallback(query, i18n.T(lang, "alert.update_error", err), true)
+			return
+		}
+		t.answerCallback(query, i18n.T(lang, "callback.snoozed", alert.ID, until.In(loc).Format(layout)), false)
+
+	case callbackEdit:
+		t.saveConversation(chatID, &conversation{step: stepEditThreshold, alertID: id})
+		t.answerCallback(query, "", false)
+		t.sendMessage(chatID, i18n.T(lang, "callback.edit_prompt", alert.ID, alertSubject(lang, &alert), formatThreshold(lang, &alert)))
+
+	default:
+		t.answerCallback(query, i18n.T(lang, "callback.unknown_action"), true)
+	}
+}
+
+// answerCallback conferma a Telegram la gestione di un pulsante mostrando un breve messaggio
+// (una notifica temporanea oppure, con showAlert, una finestra da chiudere)
+func (t *TelegramBot) answerCallback(query *tgbotapi.CallbackQuery, text string, showAlert bool) {
+	callback := tgbotapi.NewCallback(query.ID, text)
+	callback.ShowAlert = showAlert
+	if _, err := t.bot.Request(callback); err != nil {
+		log.Printf("[Telegram] Errore nella risposta al callback: %v", err)
+	}
+}
+
+// removeAlertButtons rimuove da un messaggio i pulsanti relativi a un alert eliminato
+func (t *TelegramBot) removeAlertButtons(message *tgbotapi.Message, alertID uint) {
+	if message.ReplyMarkup == nil {
+		return
+	}
+
+	suffix := fmt.Sprintf(":%d", alertID)
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(message.ReplyMarkup.InlineKeyboard))
+	for _, row := range message.ReplyMarkup.InlineKeyboard {
+		var kept []tgbotapi.InlineKeyboardButton
+		for _, button := range row {
+			if button.CallbackData == nil || !strings.HasSuffix(*button.CallbackData, suffix) {
+				kept = append(kept, button)
+			}
+		}
+		if len(kept) > 0 {
+			rows = append(rows, kept)
+		}
+	}
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(message.Chat.ID, message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+	if _, err := t.bot.Request(edit); err != nil {
+		log.Printf("[Telegram] Errore nell'aggiornamento dei pulsanti: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/chats"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// settingsTTL è la durata delle preferenze in cache: le modifiche fatte fuori dal bot
+// (es. direttamente nel database) vengono lette al più tardi dopo questo intervallo
+const settingsTTL = 5 * time.Minute
+
+// cachedChat è una chat in cache con la sua scadenza
+type cachedChat struct {
+	chat      models.Chat
+	expiresAt time.Time
+}
+
+// registerChat registra nel database la chat da cui arriva un update, aggiornandone i dati Telegram
+// e l'ultimo accesso; una chat nuova riceve la lingua dell'utente Telegram, se supportata.
+// Con reactivate la chat torna attiva (vedi chats.Seen).
+func (t *TelegramBot) registerChat(chat *tgbotapi.Chat, from *tgbotapi.User, reactivate bool) {
+	lang := i18n.DefaultLanguage
+	if from != nil {
+		lang = i18n.Match(from.LanguageCode)
+	}
+
+	record := models.Chat{
+		ID:        chat.ID,
+		Type:      chat.Type,
+		Username:  chat.UserName,
+		FirstName: chat.FirstName,
+		LastName:  chat.LastName,
+		Title:     chat.Title,
+		Language:  lang,
+		Timezone:  models.DefaultTimezone,
+	}
+	if err := chats.Seen(t.db, record, time.Now().UTC(), reactivate); err != nil {
+		log.Printf("[Telegram] Errore nella registrazione della chat %d: %v", chat.ID, err)
+		return
+	}
+	if reactivate {
+		t.statusChanged(chat.ID, models.ChatStatusActive)
+	}
+}
+
+// markBlocked segna come bloccata una chat che non accetta più i messaggi del bot
+func (t *TelegramBot) markBlocked(chatID int64) {
+	if err := chats.SetStatus(t.db, chatID, models.ChatStatusBlocked); err != nil {
+		log.Printf("[Telegram] Errore nell'aggiornamento dello stato della chat %d: %v", chatID, err)
+		return
+	}
+	t.statusChanged(chatID, models.ChatStatusBlocked)
+	log.Printf("[Telegram] La chat %d ha bloccato il bot", chatID)
+}
+
+// sendFailed registra un errore di invio; se Telegram rifiuta il messaggio perché l'utente ha
+// bloccato il bot (o lo ha rimosso dal gruppo) la chat viene segnata come bloccata
+func (t *TelegramBot) sendFailed(chatID int64, err error) {
+	log.Printf("[Telegram] Errore nell'invio del messaggio alla chat %d: %v", chatID, err)
+
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+		t.markBlocked(chatID)
+	}
+}
+
+// chatSettings restituisce le preferenze di una chat, quelle predefinite se non è nota
+func (t *TelegramBot) chatSettings(chatID int64) models.Chat {
+	t.settingsLock.RLock()
+	cached, ok := t.settings[chatID]
+	t.settingsLock.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.chat
+	}
+
+	var chat models.Chat
+	if err := t.db.First(&chat, chatID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[Telegram] Errore nel recupero delle preferenze della chat %d: %v", chatID, err)
+		}
+		return models.Chat{ID: chatID, Language: i18n.DefaultLanguage, Timezone: models.DefaultTimezone}
+	}
+
+	t.cacheSettings(chat)
+	return chat
+}
+
+// saveSettings salva le preferenze indicate di una chat, registrandola se non esiste ancora
+func (t *TelegramBot) saveSettings(chat models.Chat, columns ...string) error {
+	now := time.Now().UTC()
+	if chat.CreatedAt.IsZero() {
+		chat.CreatedAt = now
+		chat.FirstSeenAt, chat.LastSeenAt = now, now
+	}
+	chat.UpdatedAt = now
+
+	err := t.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, "updated_at")),
+	}).Create(&chat).Error
+	if err != nil {
+		return err
+	}
+
+	t.cacheSettings(chat)
+	return nil
+}
+
+// cacheSettings memorizza le preferenze di una chat per evitare una query a ogni messaggio
+func (t *TelegramBot) cacheSettings(chat models.Chat) {
+	t.settingsLock.Lock()
+	defer t.settingsLock.Unlock()
+
+	t.settings[chat.ID] = cachedChat{chat: chat, expiresAt: time.Now().Add(settingsTTL)}
+}
+
+// statusChanged invalida la chat in cache se il suo stato nel database non è più quello memorizzato
+func (t *TelegramBot) statusChanged(chatID int64, status string) {
+	t.settingsLock.Lock()
+	defer t.settingsLock.Unlock()
+
+	if cached, ok := t.settings[chatID]; ok && cached.chat.Status != status {
+		delete(t.settings, chatID)
+	}
+}
+
+// handleMyChatMember aggiorna lo stato di una chat quando l'utente blocca o sblocca il bot,
+// o quando il bot viene rimosso o aggiunto a un gruppo
+func (t *TelegramBot) handleMyChatMember(update *tgbotapi.ChatMemberUpdated) {
+	switch update.NewChatMember.Status {
+	case "kicked", "left":
+		t.markBlocked(update.Chat.ID)
+	case "member", "administrator":
+		t.registerChat(&update.Chat, &update.From, true)
+	}
+}
@@ -0,0 +1,321 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/watchlist"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// conversationTimeout è l'inattività dopo la quale una conversazione guidata viene annullata
+const conversationTimeout = 10 * time.Minute
+
+// conversationSweepInterval è l'intervallo con cui vengono cercate le conversazioni inattive
+const conversationSweepInterval = time.Minute
+
+// maxCoinSuggestions è il numero massimo di criptovalute proposte come risposta rapida
+const maxCoinSuggestions = 6
+
+// defaultCoinSuggestions sono le criptovalute proposte quando l'utente non ne segue abbastanza
+var defaultCoinSuggestions = []string{"bitcoin", "ethereum", "solana", "ripple", "cardano", "dogecoin"}
+
+// conversationStep è il passo di una conversazione guidata in attesa di risposta
+type conversationStep int
+
+// Passi delle conversazioni guidate: creazione di un alert (/new_alert) e modifica di una soglia (pulsante ✏️)
+const (
+	stepCoin conversationStep = iota
+	stepDirection
+	stepThreshold
+	stepExpiry
+	stepEditThreshold
+)
+
+// conversation è lo stato di una conversazione guidata con una chat
+type conversation struct {
+	step      conversationStep
+	alertID   int // alert da modificare (solo stepEditThreshold)
+	coinID    string
+	price     float64
+	direction string
+	threshold float64
+	updatedAt time.Time
+}
+
+// directionKeys contiene, per ciascuna direzione, le chiavi del catalogo del pulsante e della risposta breve
+var directionKeys = []struct {
+	direction, button, word string
+}{
+	{models.DirectionAbove, "conversation.button_above", "conversation.word_above"},
+	{models.DirectionBelow, "conversation.button_below", "conversation.word_below"},
+	{models.DirectionCross, "conversation.button_cross", "conversation.word_cross"},
+}
+
+// directionChoices accetta come risposta al passo della direzione i pulsanti e le risposte brevi in tutte le lingue
+var directionChoices = func() map[string]string {
+	choices := make(map[string]string)
+	for _, lang := range i18n.Supported() {
+		for _, key := range directionKeys {
+			choices[strings.ToLower(i18n.T(lang, key.button))] = key.direction
+			choices[i18n.T(lang, key.word)] = key.direction
+		}
+	}
+	return choices
+}()
+
+// directionButtons restituisce le risposte rapide per il passo della direzione
+func directionButtons(lang string) []string {
+	buttons := make([]string, 0, len(directionKeys))
+	for _, key := range directionKeys {
+		buttons = append(buttons, i18n.T(lang, key.button))
+	}
+	return buttons
+}
+
+// expiryButtons restituisce le risposte rapide per il passo della scadenza; la prima indica nessuna scadenza
+func expiryButtons(lang string) []string {
+	return []string{i18n.T(lang, "conversation.button_no_expiry"), "24h", "7d", "30d"}
+}
+
+// isNoExpiry indica se la risposta al passo della scadenza chiede un alert senza scadenza, in qualsiasi lingua
+func isNoExpiry(text string) bool {
+	if strings.EqualFold(text, "no") {
+		return true
+	}
+	for _, lang := range i18n.Supported() {
+		if strings.EqualFold(text, expiryButtons(lang)[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleNewAlert gestisce il comando /new_alert, che avvia la creazione guidata di un alert
+func (t *TelegramBot) handleNewAlert(message *tgbotapi.Message) {
+	conv := &conversation{step: stepCoin}
+	lang := t.language(message.Chat.ID)
+
+	// L'ID della criptovaluta può essere già indicato come argomento (es. /new_alert bitcoin)
+	if args := strings.Fields(message.CommandArguments()); len(args) > 0 {
+		t.advanceConversation(message.Chat.ID, conv, args[0])
+		return
+	}
+
+	t.saveConversation(message.Chat.ID, conv)
+	t.sendReplyKeyboard(message.Chat.ID, i18n.T(lang, "conversation.start"), t.coinSuggestions(message.Chat.ID))
+}
+
+// continueConversation passa un messaggio di testo alla conversazione in corso nella chat.
+// Restituisce false se non ci sono conversazioni in corso.
+func (t *TelegramBot) continueConversation(message *tgbotapi.Message) bool {
+	conv, ok := t.takeConversation(message.Chat.ID)
+	if !ok {
+		return false
+	}
+
+	t.advanceConversation(message.Chat.ID, conv, strings.TrimSpace(message.Text))
+	return true
+}
+
+// advanceConversation elabora la risposta al passo corrente e pone la domanda successiva;
+// se la risposta non è valida ripete la domanda
+func (t *TelegramBot) advanceConversation(chatID int64, conv *conversation, text string) {
+	lang := t.language(chatID)
+
+	switch conv.step {
+	case stepCoin:
+		coinID := t.coins.Resolve(text)
+		price, err := t.getPrice(coinID, pricing.DefaultCurrency)
+		if err != nil {
+			// Propone le criptovalute più simili, se ce ne sono, al posto dei suggerimenti abituali
+			var choices []string
+			for _, match := range t.coins.Search(text, maxCoinSuggestions) {
+				choices = append(choices, match.ID)
+			}
+			if len(choices) == 0 {
+				choices = t.coinSuggestions(chatID)
+			}
+
+			t.saveConversation(chatID, conv)
+			t.sendReplyKeyboard(chatID, i18n.T(lang, "conversation.coin_not_found", coinID), choices)
+			return
+		}
+
+		conv.coinID, conv.price = coinID, price
+		conv.step = stepDirection
+		t.saveConversation(chatID, conv)
+		t.sendReplyKeyboard(chatID, i18n.T(lang, "conversation.ask_direction", coinID, pricing.FormatAmount(price, pricing.DefaultCurrency)),
+			directionButtons(lang))
+
+	case stepDirection:
+		direction, ok := directionChoices[strings.ToLower(text)]
+		if !ok {
+			var err error
+			if direction, err = models.ParseDirection(text); err != nil || text == "" {
+				t.saveConversation(chatID, conv)
+				t.sendReplyKeyboard(chatID, i18n.T(lang, "conversation.invalid_direction"), directionButtons(lang))
+				return
+			}
+		}
+
+		conv.direction = direction
+		conv.step = stepThreshold
+		t.saveConversation(chatID, conv)
+		t.sendMessageRemovingKeyboard(chatID, i18n.T(lang, "conversation.ask_threshold",
+			strings.ToUpper(pricing.DefaultCurrency), pricing.FormatAmount(conv.price, pricing.DefaultCurrency)))
+
+	case stepThreshold:
+		threshold, err := strconv.ParseFloat(strings.TrimPrefix(text, "$"), 64)
+		if err != nil || threshold <= 0 {
+			t.saveConversation(chatID, conv)
+			t.sendMessage(chatID, i18n.T(lang, "conversation.invalid_threshold"))
+			return
+		}
+
+		conv.threshold = threshold
+		conv.step = stepExpiry
+		t.saveConversation(chatID, conv)
+		t.sendReplyKeyboard(chatID, i18n.T(lang, "conversation.ask_expiry"), expiryButtons(lang))
+
+	case stepExpiry:
+		var expiresIn time.Duration
+		if !isNoExpiry(text) {
+			var err error
+			if expiresIn, err = parseDuration(strings.ToLower(text)); err != nil {
+				t.saveConversation(chatID, conv)
+				t.sendReplyKeyboard(chatID, i18n.Message(lang, err), expiryButtons(lang))
+				return
+			}
+		}
+
+		response := t.createPriceAlert(chatID, createAlertArgs{
+			CoinID:    conv.coinID,
+			Direction: conv.direction,
+			Threshold: conv.threshold,
+			Currency:  pricing.DefaultCurrency,
+			Options:   alertOptions{TriggerMode: models.TriggerModeCrossing, ExpiresIn: expiresIn},
+		})
+		t.sendMessageRemovingKeyboard(chatID, response)
+
+	case stepEditThreshold:
+		// Con una risposta non valida la modifica resta aperta in attesa di una nuova soglia
+		if !t.updateAlertThreshold(chatID, conv.alertID, strings.Fields(text)) {
+			t.saveConversation(chatID, conv)
+		}
+	}
+}
+
+// coinSuggestions propone le criptovalute seguite dall'utente (watchlist, portafoglio e alert),
+// completate con quelle più diffuse
+func (t *TelegramBot) coinSuggestions(chatID int64) []string {
+	var suggestions []string
+	add := func(coinID string) {
+		if coinID != "" && len(suggestions) < maxCoinSuggestions && !slices.Contains(suggestions, coinID) {
+			suggestions = append(suggestions, coinID)
+		}
+	}
+
+	if items, err := watchlist.Items(t.db, chatID); err == nil {
+		for _, coinID := range watchlist.CoinIDs(items) {
+			add(coinID)
+		}
+	}
+
+	var followed []string
+	t.db.Model(&models.Holding{}).Where("user_chat_id = ? AND quantity > 0", chatID).Pluck("cryptocurrency_id", &followed)
+	for _, coinID := range followed {
+		add(coinID)
+	}
+
+	followed = nil
+	t.db.Model(&models.Alert{}).Where("user_chat_id = ?", chatID).Distinct().Pluck("cryptocurrency_id", &followed)
+	for _, coinID := range followed {
+		add(coinID)
+	}
+
+	for _, coinID := range defaultCoinSuggestions {
+		add(coinID)
+	}
+
+	return suggestions
+}
+
+// saveConversation salva lo stato della conversazione di una chat aggiornando l'ultima attività
+func (t *TelegramBot) saveConversation(chatID int64, conv *conversation) {
+	t.conversationLock.Lock()
+	defer t.conversationLock.Unlock()
+
+	conv.updatedAt = time.Now()
+	t.conversations[chatID] = conv
+}
+
+// takeConversation restituisce e rimuove la conversazione in corso di una chat, se non è scaduta.
+// Mentre la risposta viene elaborata la chat non ha conversazioni attive.
+func (t *TelegramBot) takeConversation(chatID int64) (*conversation, bool) {
+	t.conversationLock.Lock()
+	defer t.conversationLock.Unlock()
+
+	conv, ok := t.conversations[chatID]
+	delete(t.conversations, chatID)
+	if !ok || time.Since(conv.updatedAt) > conversationTimeout {
+		return nil, false
+	}
+	return conv, true
+}
+
+// sweepConversations annulla periodicamente le conversazioni inattive avvisando le chat
+func (t *TelegramBot) sweepConversations() {
+	ticker := time.NewTicker(conversationSweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var expired []int64
+
+		t.conversationLock.Lock()
+		for chatID, conv := range t.conversations {
+			if time.Since(conv.updatedAt) > conversationTimeout {
+				expired = append(expired, chatID)
+				delete(t.conversations, chatID)
+			}
+		}
+		t.conversationLock.Unlock()
+
+		for _, chatID := range expired {
+			log.Printf("[Telegram] Conversazione con la chat %d annullata per inattività", chatID)
+			t.sendMessageRemovingKeyboard(chatID, i18n.T(t.language(chatID), "conversation.timeout"))
+		}
+	}
+}
+
+// sendReplyKeyboard invia un messaggio con una tastiera di risposte rapide, una per riga
+func (t *TelegramBot) sendReplyKeyboard(chatID int64, text string, choices []string) {
+	rows := make([][]tgbotapi.KeyboardButton, 0, len(choices))
+	for _, choice := range choices {
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(choice)))
+	}
+
+	keyboard := tgbotapi.NewOneTimeReplyKeyboard(rows...)
+	keyboard.ResizeKeyboard = true
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err := t.bot.Send(msg); err != nil {
+		t.sendFailed(chatID, err)
+	}
+}
+
+// sendMessageRemovingKeyboard invia un messaggio nascondendo l'eventuale tastiera di risposte rapide
+func (t *TelegramBot) sendMessageRemovingKeyboard(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(false)
+	if _, err := t.bot.Send(msg); err != nil {
+		t.sendFailed(chatID, err)
+	}
+}
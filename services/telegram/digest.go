@@ -0,0 +1,218 @@
+package telegram
+
+import (
+	"context"
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/digest"
+	"crypto-tracker/services/pricing"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+)
+
+// weekdayNames contiene i nomi (italiani e inglesi) accettati per i giorni della settimana
+var weekdayNames = map[string]time.Weekday{
+	"dom": time.Sunday, "domenica": time.Sunday, "sun": time.Sunday, "sunday": time.Sunday,
+	"lun": time.Monday, "lunedì": time.Monday, "lunedi": time.Monday, "mon": time.Monday, "monday": time.Monday,
+	"mar": time.Tuesday, "martedì": time.Tuesday, "martedi": time.Tuesday, "tue": time.Tuesday, "tuesday": time.Tuesday,
+	"mer": time.Wednesday, "mercoledì": time.Wednesday, "mercoledi": time.Wednesday, "wed": time.Wednesday, "wednesday": time.Wednesday,
+	"gio": time.Thursday, "giovedì": time.Thursday, "giovedi": time.Thursday, "thu": time.Thursday, "thursday": time.Thursday,
+	"ven": time.Friday, "venerdì": time.Friday, "venerdi": time.Friday, "fri": time.Friday, "friday": time.Friday,
+	"sab": time.Saturday, "sabato": time.Saturday, "sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// handleDigest gestisce il comando /digest
+func (t *TelegramBot) handleDigest(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	args := strings.Fields(message.CommandArguments())
+	usage := i18n.T(lang, "digest.usage")
+
+	var schedule models.DigestSchedule
+	err := t.db.Where("user_chat_id = ?", message.Chat.ID).First(&schedule).Error
+	found := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.load_error", err))
+		return
+	}
+
+	if len(args) == 0 {
+		if !found {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.not_subscribed")+"\n\n"+usage)
+			return
+		}
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.status",
+			formatDigestSchedule(lang, &schedule), schedule.NextRunAt.In(loc).Format(dateTimeLayout), usage))
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "off", "stop":
+		if !found {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.not_subscribed"))
+			return
+		}
+		if err := t.db.Delete(&schedule).Error; err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.delete_error", err))
+			return
+		}
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.unsubscribed"))
+		return
+
+	case "now":
+		// Anteprima immediata: non modifica la pianificazione né il periodo del prossimo digest
+		if !found {
+			now := time.Now().UTC()
+			schedule = models.DigestSchedule{UserChatID: message.Chat.ID, Currency: pricing.DefaultCurrency, CreatedAt: now.Add(-24 * time.Hour)}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+		defer cancel()
+
+		result, err := digest.Build(ctx, t.db, t.prices, schedule, time.Now().UTC())
+		if err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.build_error", err))
+			return
+		}
+		t.sendDigest(result)
+		return
+	}
+
+	updated, err := parseDigestArgs(args)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.Message(lang, err)+"\n\n"+usage)
+		return
+	}
+
+	now := time.Now().UTC()
+	schedule.UserChatID = message.Chat.ID
+	schedule.Frequency = updated.Frequency
+	schedule.Hour, schedule.Minute, schedule.Weekday = updated.Hour, updated.Minute, updated.Weekday
+	schedule.Currency = updated.Currency
+	if !found {
+		schedule.CreatedAt = now
+	}
+	schedule.UpdatedAt = now
+	// L'orario di invio è interpretato nel fuso orario della chat (vedi /timezone)
+	schedule.NextRunAt = schedule.NextRun(now, loc)
+
+	if err := t.db.Save(&schedule).Error; err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.save_error", err))
+		return
+	}
+
+	t.sendMessage(message.Chat.ID, i18n.T(lang, "digest.saved",
+		formatDigestSchedule(lang, &schedule), schedule.NextRunAt.In(loc).Format(dateTimeLayout)))
+}
+
+// parseDigestArgs interpreta la sintassi /digest daily|weekly [giorno] HH:MM [valuta]
+func parseDigestArgs(args []string) (models.DigestSchedule, error) {
+	schedule := models.DigestSchedule{Weekday: int(time.Monday), Currency: pricing.DefaultCurrency}
+
+	var err error
+	if schedule.Frequency, err = models.ParseDigestFrequency(args[0]); err != nil {
+		return models.DigestSchedule{}, err
+	}
+	rest := args[1:]
+
+	if schedule.Frequency == models.DigestWeekly {
+		if len(rest) < 1 {
+			return models.DigestSchedule{}, i18n.Errorf("digest.weekday_required")
+		}
+		weekday, ok := weekdayNames[strings.ToLower(rest[0])]
+		if !ok {
+			return models.DigestSchedule{}, i18n.Errorf("digest.invalid_weekday", rest[0])
+		}
+		schedule.Weekday = int(weekday)
+		rest = rest[1:]
+	}
+
+	if len(rest) < 1 {
+		return models.DigestSchedule{}, i18n.Errorf("digest.time_required")
+	}
+	if schedule.Hour, schedule.Minute, err = models.ParseClock(rest[0]); err != nil {
+		return models.DigestSchedule{}, err
+	}
+
+	if len(rest) > 1 {
+		if schedule.Currency, err = pricing.ParseCurrency(rest[1]); err != nil {
+			return models.DigestSchedule{}, err
+		}
+	}
+
+	return schedule, nil
+}
+
+// formatDigestSchedule descrive la pianificazione di un digest (es. "ogni lunedì alle 08:00, prezzi in USD")
+func formatDigestSchedule(lang string, schedule *models.DigestSchedule) string {
+	when := i18n.T(lang, "digest.every_day")
+	if schedule.Frequency == models.DigestWeekly {
+		when = i18n.T(lang, "digest.every_weekday", i18n.T(lang, "weekday."+strconv.Itoa(schedule.Weekday%7)))
+	}
+	return i18n.T(lang, "digest.schedule", when, schedule.Hour, schedule.Minute, strings.ToUpper(schedule.Currency))
+}
+
+// GetDigestChannel restituisce un canale per ricevere i digest da inviare tramite Telegram
+func (t *TelegramBot) GetDigestChannel() chan *digest.Digest {
+	digestCh := make(chan *digest.Digest, 100)
+
+	go func() {
+		for result := range digestCh {
+			err := t.sendDigest(result)
+			if result.Sent != nil {
+				result.Sent <- err
+			}
+		}
+	}()
+
+	return digestCh
+}
+
+// sendDigest formatta e invia un digest alla chat a cui è destinato
+func (t *TelegramBot) sendDigest(result *digest.Digest) error {
+	lang := t.language(result.Schedule.UserChatID)
+	loc := t.location(result.Schedule.UserChatID)
+
+	var response strings.Builder
+	response.WriteString(i18n.T(lang, "digest.header", result.GeneratedAt.In(loc).Format(dateTimeLayout)))
+
+	if result.Empty() {
+		response.WriteString(i18n.T(lang, "digest.empty"))
+		return t.deliver(result.Schedule.UserChatID, response.String())
+	}
+
+	if len(result.Watchlist) > 0 {
+		response.WriteString(fmt.Sprintf("\n👀 Watchlist (%s)\n", strings.ToUpper(result.Schedule.Currency)))
+		for _, entry := range result.Watchlist {
+			switch {
+			case !entry.PriceAvailable:
+				response.WriteString(i18n.T(lang, "watchlist.price_unavailable", entry.CryptoID))
+			case entry.Change24h != nil:
+				response.WriteString(fmt.Sprintf("%s %s: %s (%+.2f%% 24h)\n", changeSymbol(*entry.Change24h), entry.CryptoID,
+					pricing.FormatAmount(entry.Price, entry.Currency), *entry.Change24h))
+			default:
+				response.WriteString(fmt.Sprintf("▫️ %s: %s\n", entry.CryptoID, pricing.FormatAmount(entry.Price, entry.Currency)))
+			}
+		}
+	}
+
+	response.WriteString(i18n.T(lang, "digest.fired_header", result.Since.In(loc).Format(dateTimeLayout), len(result.Fired)))
+	for _, fired := range result.Fired {
+		response.WriteString(i18n.T(lang, "digest.fired_item", fired.Alert.ID, alertSubject(lang, &fired.Alert), formatThreshold(lang, &fired.Alert),
+			fired.Event.CreatedAt.In(loc).Format(shortDateTimeLayout), formatObserved(&fired.Alert, fired.Event.Price)))
+	}
+
+	response.WriteString(i18n.T(lang, "digest.active_header", len(result.ActiveAlerts)))
+	for _, alert := range result.ActiveAlerts {
+		response.WriteString(i18n.T(lang, "digest.active_item", alert.ID, alertSubject(lang, &alert), formatThreshold(lang, &alert), formatObserved(&alert, alert.CurrentPrice)))
+	}
+
+	log.Printf("[Telegram] Invio del digest alla chat %d", result.Schedule.UserChatID)
+	return t.deliver(result.Schedule.UserChatID, response.String())
+}
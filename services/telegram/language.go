@@ -0,0 +1,44 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// language restituisce la lingua preferita di una chat, quella predefinita se non è nota
+func (t *TelegramBot) language(chatID int64) string {
+	return t.chatSettings(chatID).Language
+}
+
+// handleLanguage gestisce il comando /language [it|en]
+func (t *TelegramBot) handleLanguage(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	chat := t.chatSettings(chatID)
+	current := chat.Language
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		var available []string
+		for _, lang := range i18n.Supported() {
+			available = append(available, lang+" - "+i18n.Name(lang))
+		}
+		t.sendMessage(chatID, i18n.T(current, "language.current", i18n.Name(current), strings.Join(available, "\n")))
+		return
+	}
+
+	lang, ok := i18n.Parse(args[0])
+	if !ok {
+		t.sendMessage(chatID, i18n.T(current, "language.invalid", args[0], strings.Join(i18n.Supported(), ", ")))
+		return
+	}
+
+	chat.Language = lang
+	if err := t.saveSettings(chat, "language"); err != nil {
+		t.sendMessage(chatID, i18n.T(current, "language.save_error", err))
+		return
+	}
+
+	t.sendMessage(chatID, i18n.T(lang, "language.updated", i18n.Name(lang)))
+}
@@ -0,0 +1,249 @@
+package telegram
+
+import (
+	"context"
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/portfolio"
+	"crypto-tracker/services/pricing"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleBuy gestisce il comando /buy
+func (t *TelegramBot) handleBuy(message *tgbotapi.Message) {
+	t.handleTrade(message, models.TransactionBuy)
+}
+
+// handleSell gestisce il comando /sell
+func (t *TelegramBot) handleSell(message *tgbotapi.Message) {
+	t.handleTrade(message, models.TransactionSell)
+}
+
+// handleTrade registra un acquisto o una vendita con la sintassi
+// /buy|/sell <crypto_id> <quantità> [prezzo] [valuta]; senza prezzo usa quello di mercato
+func (t *TelegramBot) handleTrade(message *tgbotapi.Message, txType string) {
+	lang := t.language(message.Chat.ID)
+	args := strings.Fields(message.CommandArguments())
+	if len(args) < 2 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "trade.usage", txType, txType))
+		return
+	}
+
+	coinID := t.coins.Resolve(args[0])
+	quantity, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || quantity <= 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "trade.invalid_quantity"))
+		return
+	}
+
+	var price float64
+	currency := pricing.DefaultCurrency
+	for _, arg := range args[2:] {
+		if value, err := strconv.ParseFloat(arg, 64); err == nil {
+			if value <= 0 {
+				t.sendMessage(message.Chat.ID, i18n.T(lang, "trade.invalid_price"))
+				return
+			}
+			price = value
+			continue
+		}
+		if currency, err = pricing.ParseCurrency(arg); err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+			return
+		}
+	}
+
+	// Senza prezzo esplicito usa il prezzo di mercato, che verifica anche l'ID
+	if price == 0 {
+		if price, err = t.getPrice(coinID, currency); err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "common.price_unavailable", coinID))
+			return
+		}
+	}
+
+	tx, holding, err := portfolio.Record(t.db, models.Transaction{
+		UserChatID: message.Chat.ID,
+		CryptoID:   coinID,
+		Type:       txType,
+		Quantity:   quantity,
+		Price:      price,
+		Currency:   currency,
+	})
+	switch {
+	case errors.Is(err, models.ErrInsufficientQuantity), errors.Is(err, portfolio.ErrCurrencyMismatch):
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+		return
+	case err != nil:
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "trade.error", err))
+		return
+	}
+
+	action := i18n.T(lang, "trade.buy_recorded")
+	if txType == models.TransactionSell {
+		action = i18n.T(lang, "trade.sell_recorded")
+	}
+
+	response := i18n.T(lang, "trade.recorded",
+		action, tx.Quantity, coinID, pricing.FormatAmount(tx.Price, currency), pricing.FormatAmount(tx.Quantity*tx.Price, currency),
+		holding.Quantity, coinID, pricing.FormatAmount(holding.AverageCost(), holding.Currency))
+	if txType == models.TransactionSell {
+		response += "\n" + i18n.T(lang, "trade.realized_pnl", formatSignedAmount(holding.RealizedPnL, holding.Currency))
+	}
+
+	t.sendMessage(message.Chat.ID, response)
+}
+
+// handlePortfolio gestisce il comando /portfolio
+func (t *TelegramBot) handlePortfolio(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	holdings, err := portfolio.Holdings(t.db, message.Chat.ID)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "portfolio.load_error", err))
+		return
+	}
+
+	if len(holdings) == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "portfolio.empty"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	summary, err := portfolio.Valuate(ctx, t.prices, holdings)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "portfolio.prices_error", err))
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(i18n.T(lang, "portfolio.header"))
+
+	for _, position := range summary.Positions {
+		response.WriteString(fmt.Sprintf("%s: %g @ %s\n", position.CryptoID, position.Quantity, pricing.FormatAmount(position.AverageCost, position.Currency)))
+		if !position.PriceAvailable {
+			response.WriteString(i18n.T(lang, "portfolio.price_unavailable"))
+			continue
+		}
+		response.WriteString(i18n.T(lang, "portfolio.position",
+			pricing.FormatAmount(position.Value, position.Currency), position.Allocation,
+			formatSignedAmount(position.UnrealizedPnL, position.Currency), position.UnrealizedPnLPercent))
+	}
+
+	for _, total := range summary.Totals {
+		response.WriteString(i18n.T(lang, "portfolio.total",
+			strings.ToUpper(total.Currency), pricing.FormatAmount(total.Value, total.Currency),
+			formatSignedAmount(total.UnrealizedPnL, total.Currency), total.UnrealizedPnLPercent,
+			formatSignedAmount(total.RealizedPnL, total.Currency)))
+	}
+
+	response.WriteString("\n" + i18n.T(lang, "common.updated_at", time.Now().In(loc).Format(dateTimeLayout)))
+	t.sendMessage(message.Chat.ID, response.String())
+}
+
+// handlePortfolioAlert gestisce il comando /portfolio_alert
+func (t *TelegramBot) handlePortfolioAlert(message *tgbotapi.Message) {
+	args, err := parsePortfolioAlertArgs(strings.Fields(message.CommandArguments()))
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.Message(t.language(message.Chat.ID), err))
+		return
+	}
+
+	now := time.Now().UTC()
+	alert := models.Alert{
+		Type:           models.AlertTypePortfolioValue,
+		Currency:       args.Currency,
+		Direction:      args.Direction,
+		ThresholdPrice: args.Threshold,
+		Baseline:       models.BaselineFixed,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		UserChatID:     message.Chat.ID,
+	}
+	args.Options.apply(&alert)
+
+	t.createPortfolioAlert(message.Chat.ID, &alert)
+}
+
+// handlePnLAlert gestisce il comando /pnl_alert
+func (t *TelegramBot) handlePnLAlert(message *tgbotapi.Message) {
+	args, err := parsePnLAlertArgs(strings.Fields(message.CommandArguments()))
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.Message(t.language(message.Chat.ID), err))
+		return
+	}
+	args.CoinID = t.coins.Resolve(args.CoinID)
+
+	// La valuta dell'alert è quella in cui è registrata la posizione
+	currency := pricing.DefaultCurrency
+	var holding models.Holding
+	if err := t.db.Where("user_chat_id = ? AND cryptocurrency_id = ?", message.Chat.ID, args.CoinID).First(&holding).Error; err == nil {
+		currency = holding.Currency
+	}
+
+	now := time.Now().UTC()
+	alert := models.Alert{
+		CryptoID:       args.CoinID,
+		Type:           models.AlertTypePositionPnL,
+		Currency:       currency,
+		Direction:      args.Direction,
+		ThresholdPrice: args.Threshold,
+		Baseline:       models.BaselineFixed,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		UserChatID:     message.Chat.ID,
+	}
+	args.Options.apply(&alert)
+
+	t.createPortfolioAlert(message.Chat.ID, &alert)
+}
+
+// createPortfolioAlert valuta il portafoglio, salva un alert sul portafoglio e invia la conferma
+func (t *TelegramBot) createPortfolioAlert(chatID int64, alert *models.Alert) {
+	lang := t.language(chatID)
+	loc := t.location(chatID)
+	current, err := t.currentValue(alert)
+	if err != nil {
+		t.sendMessage(chatID, i18n.T(lang, "portfolio.valuation_error", err))
+		return
+	}
+	alert.Observe(current, time.Now().UTC())
+
+	if err := t.db.Create(alert).Error; err != nil {
+		t.sendMessage(chatID, i18n.T(lang, "alert.create_error", err))
+		return
+	}
+
+	response := i18n.T(lang, "alert.portfolio_created",
+		alert.ID, formatSubject(lang, alert), formatThreshold(lang, alert), formatCurrent(lang, alert), alert.CreatedAt.In(loc).Format(dateTimeLayout))
+
+	if repeat := formatRepeat(lang, alert); repeat != "" {
+		response += "\n" + repeat
+	}
+	if window := formatWindow(lang, loc, alert); window != "" {
+		response += "\n" + window
+	}
+
+	if warning := alert.CreationWarning(lang, current); warning != "" {
+		response += "\n\n⚠️ " + warning
+	}
+
+	t.sendMessage(chatID, response)
+}
+
+// formatSignedAmount formatta un importo con il segno davanti al simbolo (es. "-$120.00")
+func formatSignedAmount(amount float64, currency string) string {
+	sign := "+"
+	if amount < 0 {
+		sign = "-"
+	}
+	return sign + pricing.FormatAmount(math.Abs(amount), currency)
+}
@@ -0,0 +1,207 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/chart"
+	"crypto-tracker/services/history"
+	"crypto-tracker/services/pricing"
+	"fmt"
+	"image/color"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleRange gestisce il comando /range, che riassume l'andamento delle ultime 24 ore
+func (t *TelegramBot) handleRange(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	args := strings.Fields(message.CommandArguments())
+	if len(args) < 1 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "range.usage"))
+		return
+	}
+
+	coinID := t.coins.Resolve(args[0])
+
+	currency := pricing.DefaultCurrency
+	if len(args) > 1 {
+		var err error
+		if currency, err = pricing.ParseCurrency(args[1]); err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+			return
+		}
+	}
+
+	to := time.Now().UTC()
+	samples, err := t.history.Samples(coinID, currency, to.Add(-24*time.Hour), to)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "range.error", err))
+		return
+	}
+
+	summary, ok := history.Summarize(samples)
+	if !ok {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "range.no_data", coinID, strings.ToUpper(currency)))
+		return
+	}
+
+	t.sendMessage(message.Chat.ID, i18n.T(lang, "range.summary",
+		coinID, strings.ToUpper(currency),
+		pricing.FormatAmount(summary.Open, currency),
+		pricing.FormatAmount(summary.High, currency),
+		pricing.FormatAmount(summary.Low, currency),
+		pricing.FormatAmount(summary.Close, currency),
+		summary.ChangePercent,
+		rangePercent(summary),
+		summary.From.In(loc).Format(shortDateTimeLayout), summary.To.In(loc).Format(dateTimeLayout), summary.Samples))
+}
+
+// rangePercent restituisce l'ampiezza tra minimo e massimo in percentuale del minimo
+func rangePercent(summary history.Summary) float64 {
+	if summary.Low == 0 {
+		return 0
+	}
+	return (summary.High - summary.Low) / summary.Low * 100
+}
+
+// chartPeriod descrive un periodo selezionabile con /chart
+type chartPeriod struct {
+	Duration   time.Duration
+	Interval   time.Duration // durata di ciascuna candela
+	TimeFormat string        // formato delle etichette dell'asse x
+	Label      string        // chiave del catalogo con la descrizione del periodo
+}
+
+// chartPeriods contiene i periodi accettati da /chart
+var chartPeriods = map[string]chartPeriod{
+	"24h": {Duration: 24 * time.Hour, Interval: time.Hour, TimeFormat: "15:04", Label: "chart.period_24h"},
+	"7d":  {Duration: 7 * 24 * time.Hour, Interval: 4 * time.Hour, TimeFormat: "02/01", Label: "chart.period_7d"},
+	"30d": {Duration: 30 * 24 * time.Hour, Interval: 24 * time.Hour, TimeFormat: "02/01", Label: "chart.period_30d"},
+}
+
+// thresholdColors contiene il colore delle soglie per ciascuna direzione
+var thresholdColors = map[string]color.RGBA{
+	models.DirectionAbove: chart.ColorAbove,
+	models.DirectionBelow: chart.ColorBelow,
+	models.DirectionCross: chart.ColorCross,
+}
+
+// handleChart gestisce il comando /chart, che invia il grafico dei prezzi come immagine
+func (t *TelegramBot) handleChart(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	args := strings.Fields(message.CommandArguments())
+	if len(args) < 1 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "chart.usage"))
+		return
+	}
+
+	coinID := t.coins.Resolve(args[0])
+	periodKey := "24h"
+	currency := pricing.DefaultCurrency
+	style := chart.StyleCandles
+
+	for _, arg := range args[1:] {
+		arg = strings.ToLower(arg)
+		if _, ok := chartPeriods[arg]; ok {
+			periodKey = arg
+			continue
+		}
+		if arg == "--line" {
+			style = chart.StyleLine
+			continue
+		}
+
+		var err error
+		if currency, err = pricing.ParseCurrency(arg); err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "chart.invalid_argument", err))
+			return
+		}
+	}
+	period := chartPeriods[periodKey]
+
+	to := time.Now().UTC()
+	from := to.Add(-period.Duration)
+	samples, partial, err := t.chartSamples(coinID, currency, from, to, period.Interval)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "chart.history_error", coinID, err))
+		return
+	}
+
+	candles := history.Candles(samples, period.Interval)
+	summary, _ := history.Summarize(samples)
+
+	// Le soglie degli alert attivi dell'utente su questa criptovaluta
+	var alerts []models.Alert
+	if err := t.db.Where("user_chat_id = ? AND cryptocurrency_id = ? AND currency = ? AND type IN ? AND triggered = ? AND expired = ?",
+		message.Chat.ID, coinID, currency, []string{models.AlertTypePrice, models.AlertTypePercent}, false, false).Find(&alerts).Error; err != nil {
+		log.Printf("[Telegram] Errore nel recupero degli alert per il grafico: %v", err)
+	}
+
+	var lines []chart.Line
+	for _, alert := range alerts {
+		for _, threshold := range alert.Thresholds() {
+			lines = append(lines, chart.Line{Price: threshold, Color: thresholdColors[alert.Direction]})
+		}
+	}
+
+	var buf bytes.Buffer
+	err = chart.Render(&buf, chart.Options{
+		Title:      fmt.Sprintf("%s - %s - %s", coinID, periodKey, currency),
+		Candles:    candles,
+		Style:      style,
+		Lines:      lines,
+		Location:   loc,
+		TimeFormat: period.TimeFormat,
+	})
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "chart.render_error", err))
+		return
+	}
+
+	caption := i18n.T(lang, "chart.caption",
+		coinID, i18n.T(lang, period.Label), summary.ChangePercent,
+		pricing.FormatAmount(summary.Low, currency), pricing.FormatAmount(summary.High, currency), pricing.FormatAmount(summary.Close, currency))
+	if len(lines) > 0 {
+		caption += "\n" + i18n.T(lang, "chart.threshold_lines", len(alerts))
+	}
+	if partial {
+		caption += "\n" + i18n.T(lang, "chart.partial_history", summary.From.In(loc).Format(dateTimeLayout))
+	}
+
+	photo := tgbotapi.NewPhoto(message.Chat.ID, tgbotapi.FileBytes{Name: coinID + ".png", Bytes: buf.Bytes()})
+	photo.Caption = caption
+	if _, err := t.bot.Send(photo); err != nil {
+		t.sendFailed(message.Chat.ID, err)
+	}
+}
+
+// chartSamples restituisce i prezzi del periodo dallo storico salvato, anche se copre solo
+// una parte del periodo (partial è true se inizia dopo il primo intervallo): ogni richiesta
+// dello storico alla sorgente consuma il budget condiviso con il monitor degli alert, quindi
+// la sorgente viene interrogata solo per le criptovalute di cui non è salvato alcun prezzo
+func (t *TelegramBot) chartSamples(coinID, currency string, from, to time.Time, interval time.Duration) (samples []models.PriceSample, partial bool, err error) {
+	stored, err := t.history.Samples(coinID, currency, from, to)
+	if err != nil {
+		log.Printf("[Telegram] Errore nella lettura dello storico di %s: %v", coinID, err)
+	}
+	if len(stored) > 0 {
+		return stored, !stored[0].SampledAt.Before(from.Add(interval)), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	points, err := pricing.GetHistory(ctx, t.prices, coinID, currency, from, to)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return history.SamplesFromPoints(coinID, currency, points), false, nil
+}
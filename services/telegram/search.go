@@ -0,0 +1,67 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxSearchResults è il numero di risultati mostrati da /search
+const maxSearchResults = 8
+
+// maxDidYouMean è il numero di suggerimenti proposti per un ID non riconosciuto
+const maxDidYouMean = 3
+
+// handleSearch gestisce il comando /search <testo>
+func (t *TelegramBot) handleSearch(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	query := strings.TrimSpace(message.CommandArguments())
+	if query == "" {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "search.usage"))
+		return
+	}
+	if t.coins.Size() == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "search.unavailable"))
+		return
+	}
+
+	matches := t.coins.Search(query, maxSearchResults)
+	if len(matches) == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "search.no_results", query))
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(i18n.T(lang, "search.header", query))
+	for i, match := range matches {
+		fmt.Fprintf(&response, "%d. %s (%s) - ID: %s", i+1, match.Name, strings.ToUpper(match.Symbol), match.ID)
+		if match.MarketCapRank > 0 {
+			response.WriteString(i18n.T(lang, "search.rank", match.MarketCapRank))
+		}
+		response.WriteString("\n")
+	}
+	response.WriteString(i18n.T(lang, "search.hint", matches[0].ID))
+
+	t.sendMessage(message.Chat.ID, response.String())
+}
+
+// didYouMean suggerisce le criptovalute più simili a un ID non riconosciuto dal registro;
+// restituisce una stringa vuota se l'ID è noto o non ci sono suggerimenti
+func (t *TelegramBot) didYouMean(lang, query string) string {
+	if _, ok := t.coins.Lookup(query); ok {
+		return ""
+	}
+
+	matches := t.coins.Search(query, maxDidYouMean)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	suggestions := make([]string, 0, len(matches))
+	for _, match := range matches {
+		suggestions = append(suggestions, fmt.Sprintf("%s (%s)", match.ID, strings.ToUpper(match.Symbol)))
+	}
+	return "\n\n" + i18n.T(lang, "search.did_you_mean", strings.Join(suggestions, ", "))
+}
@@ -0,0 +1,72 @@
+package telegram
+
+import (
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"errors"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+)
+
+// Formati delle date mostrate agli utenti; MST è l'abbreviazione del fuso orario della chat (es. CEST)
+const (
+	dateTimeLayout      = "02/01/2006 15:04 MST"
+	shortDateTimeLayout = "02/01 15:04"
+	timeLayout          = "15:04 MST"
+	secondsLayout       = "15:04:05 MST"
+)
+
+// location restituisce il fuso orario in cui mostrare le date a una chat
+func (t *TelegramBot) location(chatID int64) *time.Location {
+	chat := t.chatSettings(chatID)
+	return chat.Location()
+}
+
+// handleTimezone gestisce il comando /timezone [fuso_orario]
+func (t *TelegramBot) handleTimezone(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	chat := t.chatSettings(chatID)
+	lang := chat.Language
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		t.sendMessage(chatID, i18n.T(lang, "timezone.current", chat.Location().String(), time.Now().In(chat.Location()).Format(dateTimeLayout)))
+		return
+	}
+
+	timezone, err := models.ParseTimezone(args[0])
+	if err != nil {
+		t.sendMessage(chatID, i18n.Message(lang, err)+"\n\n"+i18n.T(lang, "timezone.usage"))
+		return
+	}
+
+	chat.Timezone = timezone
+	if err := t.saveSettings(chat, "timezone"); err != nil {
+		t.sendMessage(chatID, i18n.T(lang, "timezone.save_error", err))
+		return
+	}
+
+	response := i18n.T(lang, "timezone.updated", timezone, time.Now().In(chat.Location()).Format(dateTimeLayout))
+
+	// Il digest segue il fuso orario della chat: l'orario di invio resta lo stesso, nel nuovo fuso
+	var schedule models.DigestSchedule
+	err = t.db.Where("user_chat_id = ?", chatID).First(&schedule).Error
+	switch {
+	case err == nil:
+		now := time.Now().UTC()
+		schedule.NextRunAt = schedule.NextRun(now, chat.Location())
+		schedule.UpdatedAt = now
+		if err := t.db.Save(&schedule).Error; err != nil {
+			response += "\n\n" + i18n.T(lang, "digest.save_error", err)
+			break
+		}
+		response += "\n\n" + i18n.T(lang, "timezone.digest_rescheduled", schedule.NextRunAt.In(chat.Location()).Format(dateTimeLayout))
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		response += "\n\n" + i18n.T(lang, "digest.load_error", err)
+	}
+
+	t.sendMessage(chatID, response)
+}
@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"context"
+	"crypto-tracker/i18n"
+	"crypto-tracker/services/coins"
+	"crypto-tracker/services/pricing"
+	"crypto-tracker/services/watchlist"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleWatch gestisce il comando /watch <crypto_id> [crypto_id...]
+func (t *TelegramBot) handleWatch(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	coinIDs := watchArgs(t.coins, message.CommandArguments())
+	if len(coinIDs) == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.watch_usage"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	// Verifica con un'unica richiesta che tutti gli ID abbiano un prezzo
+	quotes, err := pricing.GetQuotes(ctx, t.prices, coinIDs, pricing.DefaultCurrency)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.verify_error", err))
+		return
+	}
+
+	var valid, unknown []string
+	for _, coinID := range coinIDs {
+		if _, ok := quotes[coinID]; ok {
+			valid = append(valid, coinID)
+		} else {
+			unknown = append(unknown, coinID)
+		}
+	}
+
+	var response strings.Builder
+	if len(valid) > 0 {
+		added, err := watchlist.Add(t.db, message.Chat.ID, valid)
+		switch {
+		case errors.Is(err, watchlist.ErrFull):
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+			return
+		case err != nil:
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.update_error", err))
+			return
+		}
+
+		if len(added) > 0 {
+			response.WriteString(i18n.T(lang, "watchlist.added", strings.Join(added, ", ")))
+		}
+		if len(added) < len(valid) {
+			var already []string
+			for _, coinID := range valid {
+				if !slices.Contains(added, coinID) {
+					already = append(already, coinID)
+				}
+			}
+			response.WriteString(i18n.T(lang, "watchlist.already_present", strings.Join(already, ", ")))
+		}
+	}
+	if len(unknown) > 0 {
+		response.WriteString(i18n.T(lang, "watchlist.not_found", strings.Join(unknown, ", ")))
+	}
+	response.WriteString(i18n.T(lang, "watchlist.hint"))
+
+	t.sendMessage(message.Chat.ID, response.String())
+}
+
+// handleUnwatch gestisce il comando /unwatch <crypto_id> [crypto_id...]
+func (t *TelegramBot) handleUnwatch(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	coinIDs := watchArgs(t.coins, message.CommandArguments())
+	if len(coinIDs) == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.unwatch_usage"))
+		return
+	}
+
+	removed, err := watchlist.Remove(t.db, message.Chat.ID, coinIDs)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.update_error", err))
+		return
+	}
+
+	if removed == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.none_removed"))
+		return
+	}
+
+	t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.removed", removed))
+}
+
+// handleWatchlist gestisce il comando /watchlist [valuta]
+func (t *TelegramBot) handleWatchlist(message *tgbotapi.Message) {
+	lang := t.language(message.Chat.ID)
+	loc := t.location(message.Chat.ID)
+	currency := pricing.DefaultCurrency
+	if args := strings.Fields(message.CommandArguments()); len(args) > 0 {
+		var err error
+		if currency, err = pricing.ParseCurrency(args[0]); err != nil {
+			t.sendMessage(message.Chat.ID, i18n.T(lang, "common.error", err))
+			return
+		}
+	}
+
+	items, err := watchlist.Items(t.db, message.Chat.ID)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.load_error", err))
+		return
+	}
+
+	if len(items) == 0 {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.empty"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+	defer cancel()
+
+	entries, err := watchlist.Snapshot(ctx, t.prices, watchlist.CoinIDs(items), currency)
+	if err != nil {
+		t.sendMessage(message.Chat.ID, i18n.T(lang, "watchlist.prices_error", err))
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(i18n.T(lang, "watchlist.header", strings.ToUpper(currency)))
+
+	for _, entry := range entries {
+		switch {
+		case !entry.PriceAvailable:
+			response.WriteString(i18n.T(lang, "watchlist.price_unavailable", entry.CryptoID))
+		case entry.Change24h != nil:
+			response.WriteString(fmt.Sprintf("%s %s: %s (%+.2f%% 24h)\n", changeSymbol(*entry.Change24h), entry.CryptoID,
+				pricing.FormatAmount(entry.Price, currency), *entry.Change24h))
+		default:
+			response.WriteString(fmt.Sprintf("▫️ %s: %s\n", entry.CryptoID, pricing.FormatAmount(entry.Price, currency)))
+		}
+	}
+
+	response.WriteString("\n" + i18n.T(lang, "common.updated_at", time.Now().In(loc).Format(dateTimeLayout)))
+	t.sendMessage(message.Chat.ID, response.String())
+}
+
+// watchArgs estrae gli ID delle criptovalute (separati da spazi o virgole) senza duplicati,
+// risolvendo simboli e nomi tramite il registro
+func watchArgs(registry *coins.Registry, arguments string) []string {
+	var coinIDs []string
+	for _, field := range strings.FieldsFunc(arguments, func(r rune) bool { return r == ' ' || r == ',' }) {
+		coinID := registry.Resolve(field)
+		if !slices.Contains(coinIDs, coinID) {
+			coinIDs = append(coinIDs, coinID)
+		}
+	}
+	return coinIDs
+}
+
+// changeSymbol restituisce l'indicatore mostrato accanto a una variazione percentuale
+func changeSymbol(change float64) string {
+	switch {
+	case change > 0:
+		return "📈"
+	case change < 0:
+		return "📉"
+	default:
+		return "➖"
+	}
+}
@@ -0,0 +1,115 @@
+package watchlist
+
+import (
+	"context"
+	"crypto-tracker/i18n"
+	"crypto-tracker/models"
+	"crypto-tracker/services/pricing"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxItems è il numero massimo di criptovalute in una watchlist, così che lo snapshot
+// resti entro una singola richiesta di prezzi
+const MaxItems = 50
+
+// ErrFull indica che la watchlist ha già raggiunto MaxItems elementi
+var ErrFull = i18n.Errorf("watchlist.full", MaxItems)
+
+// Entry è una criptovaluta della watchlist con la sua quotazione
+type Entry struct {
+	CryptoID       string    `json:"crypto_id"`
+	Currency       string    `json:"currency"`
+	Price          float64   `json:"price"`
+	Change24h      *float64  `json:"change_24h,omitempty"`
+	PriceAvailable bool      `json:"price_available"`
+	AsOf           time.Time `json:"as_of,omitzero"`
+}
+
+// Items restituisce le criptovalute nella watchlist di un utente, in ordine di inserimento
+func Items(db *gorm.DB, userChatID int64) ([]models.WatchlistItem, error) {
+	var items []models.WatchlistItem
+	err := db.Where("user_chat_id = ?", userChatID).Order("created_at, id").Find(&items).Error
+	return items, err
+}
+
+// CoinIDs restituisce gli ID delle criptovalute di una watchlist
+func CoinIDs(items []models.WatchlistItem) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.CryptoID)
+	}
+	return ids
+}
+
+// Add aggiunge criptovalute alla watchlist di un utente, ignorando quelle già presenti.
+// Restituisce gli ID effettivamente aggiunti.
+func Add(db *gorm.DB, userChatID int64, coinIDs []string) ([]string, error) {
+	var added []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		items, err := Items(tx, userChatID)
+		if err != nil {
+			return err
+		}
+		existing := CoinIDs(items)
+
+		now := time.Now().UTC()
+		var newItems []models.WatchlistItem
+		for _, coinID := range coinIDs {
+			if slices.Contains(existing, coinID) || slices.Contains(added, coinID) {
+				continue
+			}
+			added = append(added, coinID)
+			newItems = append(newItems, models.WatchlistItem{UserChatID: userChatID, CryptoID: coinID, CreatedAt: now})
+		}
+
+		if len(newItems) == 0 {
+			return nil
+		}
+		if len(existing)+len(newItems) > MaxItems {
+			return ErrFull
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newItems).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// Remove rimuove criptovalute dalla watchlist di un utente e restituisce quante sono state rimosse
+func Remove(db *gorm.DB, userChatID int64, coinIDs []string) (int64, error) {
+	result := db.Where("user_chat_id = ? AND cryptocurrency_id IN ?", userChatID, coinIDs).Delete(&models.WatchlistItem{})
+	return result.RowsAffected, result.Error
+}
+
+// Snapshot quota tutte le criptovalute indicate con un'unica richiesta di prezzi,
+// mantenendo l'ordine della watchlist
+func Snapshot(ctx context.Context, prices pricing.PriceProvider, coinIDs []string, currency string) ([]Entry, error) {
+	if len(coinIDs) == 0 {
+		return nil, nil
+	}
+
+	quotes, err := pricing.GetQuotes(ctx, prices, coinIDs, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(coinIDs))
+	for _, coinID := range coinIDs {
+		entry := Entry{CryptoID: coinID, Currency: currency}
+		if quote, ok := quotes[coinID]; ok {
+			entry.Price = quote.Price
+			entry.Change24h = quote.Change24h
+			entry.PriceAvailable = true
+			entry.AsOf = quote.AsOf
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}